@@ -0,0 +1,154 @@
+package fs
+
+import (
+	"os"
+	"strings"
+)
+
+// Chroot is a view of the filesystem confined to a root directory. The paths
+// given to its methods are relative to the root, are resolved with SecureJoin,
+// and can't lead outside of it, even through symbolic links.
+type Chroot struct {
+	root Path
+}
+
+// Root returns a view of the filesystem confined to the given directory
+func Root(path Path) *Chroot {
+	return &Chroot{root: path.Clean()}
+}
+
+// Root returns the directory the view is confined to
+func (c *Chroot) Root() Path {
+	return c.root
+}
+
+// Path returns the path of the name inside the root, as resolved by SecureJoin
+func (c *Chroot) Path(name string) (Path, error) {
+	return c.root.SecureJoin(name)
+}
+
+// Exists returns true if the given name exists inside the root
+func (c *Chroot) Exists(name string) bool {
+	path, err := c.Path(name)
+	return err == nil && path.Exists()
+}
+
+// Open opens the file with the given name for reading
+func (c *Chroot) Open(name string) (*os.File, error) {
+	path, err := c.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	return path.Open()
+}
+
+// Create opens the file with the given name for writing, creating a new file
+// and its parents if necessary. If the file already exists, it is overridden.
+func (c *Chroot) Create(name string) (*os.File, error) {
+	path, err := c.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	return path.Create()
+}
+
+// Append works like create, but instead of discarding the content of an existing file,
+// it just appends the new data at the end of the file.
+func (c *Chroot) Append(name string) (*os.File, error) {
+	path, err := c.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	return path.Append()
+}
+
+// ReadAll returns all the content of the file with the given name
+func (c *Chroot) ReadAll(name string) ([]byte, error) {
+	path, err := c.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	return path.ReadAll()
+}
+
+// MkdirAll creates the directory with the given name, along with its parents
+func (c *Chroot) MkdirAll(name string) error {
+	path, err := c.Path(name)
+	if err != nil {
+		return err
+	}
+	return path.MkdirAll()
+}
+
+// RemoveAll removes the file or directory with the given name. When it is a
+// symbolic link, the link itself is removed. The root itself can't be removed.
+func (c *Chroot) RemoveAll(name string) error {
+	path, err := c.linkPath(name)
+	if err != nil {
+		return err
+	}
+	return path.RemoveWithOptions(RemoveOptions{Root: c.root})
+}
+
+// CopyTo copies the file or directory with the given name to the destination,
+// both inside the root. Symbolic links are copied as links instead of being
+// followed, so their targets are never read, and every path of the destination
+// is resolved inside the root, failing with ErrIsSymlink instead of writing a
+// file through a link.
+func (c *Chroot) CopyTo(name, dest string) error {
+	src, err := c.linkPath(name)
+	if err != nil {
+		return err
+	}
+
+	destPath, err := c.Path(dest)
+	if err != nil {
+		return err
+	}
+
+	copier := &copier{
+		opts:  CopyOptions{Symlinks: SymlinkPreserve},
+		links: make(map[fileID]Path),
+		root:  c.root,
+	}
+	return copier.copy(src, destPath)
+}
+
+// Walk walks recursively on the directory with the given name, as done by
+// Path.Walk, with the paths given to the walker relative to the root. Symbolic
+// links are not followed.
+func (c *Chroot) Walk(name string, walkType WalkType, walker func(path Path, isDirectory bool) error) error {
+	path, err := c.Path(name)
+	if err != nil {
+		return err
+	}
+
+	opts := WalkOptions{Type: walkType, Sort: SortByName}
+	return path.WalkDir(opts, func(entry *DirEntry) error {
+		rel, err := entry.Path().Rel(c.root)
+		if err != nil {
+			return err
+		}
+		return walker(rel, entry.IsDir())
+	})
+}
+
+// linkPath works like Path, but without following the last component of the
+// name when it is a symbolic link.
+func (c *Chroot) linkPath(name string) (Path, error) {
+	components := splitComponents(name)
+
+	last := len(components) - 1
+	if last < 0 || components[last] == "." || components[last] == ".." {
+		return c.Path(name)
+	}
+
+	parent, err := c.Path(strings.Join(components[:last], "/"))
+	if err != nil {
+		if escape, ok := err.(*EscapeError); ok {
+			escape.Untrusted = name
+		}
+		return "", err
+	}
+	return parent.Join(components[last]), nil
+}
@@ -0,0 +1,180 @@
+package fs_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestChroot(t *testing.T) {
+	WithTempDir(func(dir string) {
+		base := fs.Path(dir)
+		outside := base.Join("outside")
+
+		if err := outside.Join("secret").Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+
+		root := fs.Root(base.Join("root"))
+		if err := root.MkdirAll("a/b"); err != nil {
+			t.Errorf("Error creating directory: %v", err)
+			return
+		}
+
+		file, err := root.Create("/a/b/file.txt")
+		if err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+		_, _ = file.Write([]byte("content"))
+		file.Close()
+
+		if err := root.Root().Join("out").Symlink(outside); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+		if err := root.Root().Join("in").Symlink("a/b"); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		if data, err := root.ReadAll("in/file.txt"); err != nil || string(data) != "content" {
+			t.Errorf("Error testing read through link: expected 'content', received '%s' (%v)", data, err)
+		}
+
+		if f, err := root.Open("a/../a/b/file.txt"); err != nil {
+			t.Errorf("Error testing open: %v", err)
+		} else {
+			f.Close()
+		}
+
+		if !root.Exists("a/b") || root.Exists("missing") || root.Exists("../outside") {
+			t.Errorf("Error testing exists inside the root")
+		}
+
+		tests := []error{
+			errorOf(root.Open("../outside/secret")),
+			errorOf(root.Open("out/secret")),
+			errorOf(root.Create("a/../../outside/new")),
+			errorOf(root.Append("out/secret")),
+			errorOf(root.ReadAll("out/secret")),
+			root.MkdirAll("out/dir"),
+			root.RemoveAll("out/secret"),
+			root.RemoveAll(""),
+			root.RemoveAll("a/.."),
+			root.CopyTo("out/secret", "copy"),
+			root.CopyTo("a", "../copy"),
+			root.Walk("out", fs.WalkBoth, func(fs.Path, bool) error { return nil }),
+		}
+
+		for i, err := range tests {
+			if !errors.Is(err, fs.ErrOutsideRoot) {
+				t.Errorf("Case %d, error testing escape of the root: expected '%v', received '%v'", i, fs.ErrOutsideRoot, err)
+			}
+		}
+
+		if !outside.Join("secret").FileExists() || outside.Join("dir").Exists() || outside.Join("new").Exists() {
+			t.Errorf("Paths outside the root should not be changed")
+		}
+
+		if err := root.CopyTo("a", "c"); err != nil {
+			t.Errorf("Error testing copy: %v", err)
+		}
+
+		var walked []fs.Path
+		err = root.Walk("", fs.WalkFiles, func(path fs.Path, isDirectory bool) error {
+			walked = append(walked, path)
+			return nil
+		})
+
+		expected := []fs.Path{"a/b/file.txt", "c/b/file.txt", "in", "out"}
+		if err != nil || !reflect.DeepEqual(walked, expected) {
+			t.Errorf("Error testing walk: expected '%v', received '%v' (%v)", expected, walked, err)
+		}
+
+		// links are removed, instead of what they point to
+		if err := root.RemoveAll("out"); err != nil {
+			t.Errorf("Error removing link: %v", err)
+		}
+
+		if err := root.RemoveAll("c"); err != nil {
+			t.Errorf("Error removing directory: %v", err)
+		}
+
+		if root.Exists("c") || root.Root().Join("out").IsSymlink() || !outside.Join("secret").Exists() {
+			t.Errorf("Error testing remove all inside the root")
+		}
+	})
+}
+
+func TestChrootCopyToLinks(t *testing.T) {
+	WithTempDir(func(dir string) {
+		base := fs.Path(dir)
+		secret := base.Join("outside/secret")
+
+		if err := secret.Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+
+		root := fs.Root(base.Join("root"))
+		for _, name := range []string{"a/f", "a/d/f"} {
+			file, err := root.Create(name)
+			if err != nil {
+				t.Errorf("Error creating file: %v", err)
+				return
+			}
+			_, _ = file.Write([]byte("content"))
+			file.Close()
+		}
+
+		// the destinations already hold links leading outside the root
+		links := map[string]fs.Path{
+			"c/f":   "../../outside/secret",
+			"e/d":   "../../outside",
+			"g/f":   "../../outside/secret",
+			"h/out": "../../outside",
+		}
+
+		for link, target := range links {
+			path := root.Root().Join(link)
+			if err := path.Parent().MkdirAll(); err != nil {
+				t.Errorf("Error creating directory: %v", err)
+				return
+			}
+			if err := path.Symlink(target); err != nil {
+				t.Errorf("Error creating link: %v", err)
+				return
+			}
+		}
+
+		tests := []struct {
+			name     string
+			dest     string
+			expected error
+		}{
+			{name: "a", dest: "c", expected: fs.ErrIsSymlink},
+			{name: "a", dest: "e", expected: fs.ErrOutsideRoot},
+			{name: "a/f", dest: "g", expected: fs.ErrIsSymlink},
+			{name: "a/f", dest: "g/f", expected: fs.ErrOutsideRoot},
+			{name: "a/f", dest: "h/out/secret", expected: fs.ErrOutsideRoot},
+		}
+
+		for i, test := range tests {
+			if err := root.CopyTo(test.name, test.dest); !errors.Is(err, test.expected) {
+				t.Errorf("Case %d, error testing copy to '%s': expected '%v', received '%v'", i, test.dest, test.expected, err)
+			}
+
+			if data, err := secret.ReadAll(); err != nil || len(data) > 0 {
+				t.Errorf("Case %d, file outside the root should not be changed: received '%s' (%v)", i, data, err)
+			}
+
+			if secret.Parent().Join("f").Exists() || secret.Parent().Join("secret/f").Exists() {
+				t.Errorf("Case %d, paths outside the root should not be created", i)
+			}
+		}
+	})
+}
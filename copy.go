@@ -0,0 +1,352 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// SymlinkMode determines how symbolic links are handled when copying
+type SymlinkMode uint
+
+const (
+	// SymlinkFollow copies the files and directories the links point to
+	SymlinkFollow SymlinkMode = iota
+
+	// SymlinkPreserve creates links in the destination pointing to the same
+	// targets as the links in the source
+	SymlinkPreserve
+
+	// SymlinkSkip ignores the links
+	SymlinkSkip
+)
+
+// CopyOptions configures the copy done by CopyToWithOptions
+type CopyOptions struct {
+	// Symlinks determines how symbolic links are handled
+	Symlinks SymlinkMode
+
+	// PreserveHardLinks makes files linked to each other in the source be linked
+	// to each other in the destination too, instead of copying their data again.
+	// It is only supported on Unix systems, failing with ErrNotSupported elsewhere.
+	PreserveHardLinks bool
+
+	// PreserveXattrs makes the extended attributes of the source be set in the
+	// destination too. Attributes outside of the `user.` namespace, like the
+	// `security.` and `trusted.` ones, are skipped when they can't be copied
+	// for lack of permissions or support.
+	PreserveXattrs bool
+
+	// PreserveTimes makes the access and modification times of the source be
+	// set in the destination too. The times of symbolic links copied as links
+	// are only preserved on Unix systems.
+	PreserveTimes bool
+}
+
+// CopyToWithOptions works like CopyTo, with the behavior configured by the options
+func (p Path) CopyToWithOptions(dest Path, opts CopyOptions) error {
+	c := &copier{opts: opts, links: make(map[fileID]Path)}
+	return c.copy(p, dest)
+}
+
+// copier holds the state of a single copy
+type copier struct {
+	opts CopyOptions
+
+	// links are the destinations of the files already copied with more than one hard link
+	links map[fileID]Path
+
+	// dirs are the directories copied, whose times are only preserved after
+	// their content is copied
+	dirs []copiedDir
+
+	// root is the directory the destinations are confined to, when not empty
+	root Path
+}
+
+// copiedDir is a directory copied and the information of its source, taken
+// before its content was read
+type copiedDir struct {
+	info os.FileInfo
+	dest Path
+}
+
+// confine returns the path to copy to. When the copy is confined to a root,
+// the destination is resolved again inside of it, so the links found in the
+// destination, even the ones copied, can't lead outside of it. The last
+// component is only followed when follow is set.
+func (c *copier) confine(dest Path, follow bool) (Path, error) {
+	if c.root == "" {
+		return dest, nil
+	}
+
+	rel, err := dest.Rel(c.root)
+	if err != nil {
+		return "", err
+	}
+
+	if follow {
+		return Root(c.root).Path(rel.String())
+	}
+	return Root(c.root).linkPath(rel.String())
+}
+
+// copy copy one path to another
+func (c *copier) copy(src, dest Path) error {
+	if src.IsSymlink() && c.opts.Symlinks != SymlinkFollow {
+		if dest.DirExists() {
+			dest = dest.Join(src.Basename())
+		}
+		return c.copySymlink(src, dest)
+	}
+
+	info, err := stat("copy", src, ErrNotFound)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode().IsRegular() {
+		if dest.DirExists() {
+			return c.copyFiles(src, dest.Join(src.Basename()))
+		}
+		return c.copyFiles(src, dest)
+	}
+
+	if dest.FileExists() {
+		return newError("copy", dest, ErrPathIsDirectoryDestFile)
+	}
+
+	return c.copyDirs(src, dest)
+}
+
+// copyDirs copy one dir to another
+func (c *copier) copyDirs(src, dest Path) error {
+	dest, err := c.confine(dest, true)
+	if err != nil {
+		return err
+	}
+
+	if !dest.DirExists() {
+		if err := dest.MkdirAll(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.copyDirAttributes(src, dest); err != nil {
+		return err
+	}
+
+	opts := WalkOptions{FollowSymlinks: c.opts.Symlinks == SymlinkFollow}
+	err = src.WalkDir(opts, func(entry *DirEntry) error {
+		path := entry.Path()
+		newDest, err := path.Rebase(src, dest)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsSymlink():
+			// only links not followed, or dangling ones, are still links
+			if c.opts.Symlinks == SymlinkFollow {
+				return newError("copy", path, ErrNotFound)
+			}
+			return c.copySymlink(path, newDest)
+		case entry.IsDir():
+			if newDest, err = c.confine(newDest, true); err != nil {
+				return err
+			}
+			if err := newDest.MkdirAll(); err != nil {
+				return err
+			}
+			return c.copyDirAttributes(path, newDest)
+		default:
+			return c.copyFiles(path, newDest)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// the times of the directories are only set once all their content was
+	// copied, as creating entries in a directory changes its modification time
+	for i := range c.dirs {
+		if err := c.copyTimes(c.dirs[i].info, c.dirs[i].dest); err != nil {
+			return err
+		}
+	}
+	c.dirs = nil
+
+	return nil
+}
+
+// copyFiles copy one file to another
+func (c *copier) copyFiles(src, dest Path) error {
+	info, err := stat("copy", src, ErrFileDoesNotExist)
+	if err != nil {
+		return err
+	}
+
+	if dest, err = c.confine(dest, false); err != nil {
+		return err
+	}
+
+	if c.opts.PreserveHardLinks {
+		id, count, ok := hardLinksOf(info)
+		if !ok {
+			return newError("copy", src, ErrNotSupported)
+		}
+
+		if count > 1 {
+			if linked, ok := c.links[id]; ok {
+				return c.link(linked, dest)
+			}
+			c.links[id] = dest
+		}
+	}
+
+	srcFile, err := open(src, openFileFlag, 0400) //r--------
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	// inside a root, links in the destination are never written through
+	opts := OpenOptions{NoFollow: c.root != ""}
+	destFile, err := DefaultFilesystem.openFile(dest, createFileFlag, info.Mode(), opts)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	_, err = io.Copy(destFile, srcFile)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if err := c.copyAttributes(src, dest); err != nil {
+		return err
+	}
+
+	return c.copyTimes(info, dest)
+}
+
+// copyDirAttributes copies the attributes of a directory, leaving its times to
+// be copied when its content was copied.
+func (c *copier) copyDirAttributes(src, dest Path) error {
+	if c.opts.PreserveTimes {
+		info, err := os.Stat(src.String())
+		if err != nil {
+			return wrapError(err)
+		}
+		c.dirs = append(c.dirs, copiedDir{info: info, dest: dest})
+	}
+	return c.copyAttributes(src, dest)
+}
+
+// copyTimes sets the access and modification times of the source, described
+// by its information, in the destination when requested by the options.
+func (c *copier) copyTimes(info os.FileInfo, dest Path) error {
+	if !c.opts.PreserveTimes {
+		return nil
+	}
+
+	atime, ok := accessTimeOf(info)
+	if !ok {
+		atime = info.ModTime()
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		return lchtimes(dest, atime, info.ModTime())
+	}
+	return dest.Chtimes(atime, info.ModTime())
+}
+
+// copyAttributes copies the attributes of the source to the destination, as
+// requested by the options.
+func (c *copier) copyAttributes(src, dest Path) error {
+	if c.opts.PreserveXattrs {
+		names, err := src.ListXattr()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if err := copyXattr(src, dest, name); err != nil && !skipXattr(name, err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyXattr copies a single extended attribute of the source to the destination
+func copyXattr(src, dest Path, name string) error {
+	value, err := src.GetXattr(name)
+	if err != nil {
+		return err
+	}
+	return dest.SetXattr(name, value)
+}
+
+// skipXattr returns true when the error copying the extended attribute is only
+// due to the privileges needed by its namespace
+func skipXattr(name string, err error) bool {
+	if strings.HasPrefix(name, "user.") {
+		return false
+	}
+	return errors.Is(err, ErrPermissionDenied) || errors.Is(err, ErrNotSupported)
+}
+
+// copySymlink creates a link in the destination pointing to the same target
+// as the source link, unless links are skipped.
+func (c *copier) copySymlink(src, dest Path) error {
+	if c.opts.Symlinks == SymlinkSkip {
+		return nil
+	}
+
+	target, err := src.Readlink()
+	if err != nil {
+		return err
+	}
+
+	if dest, err = c.confine(dest, false); err != nil {
+		return err
+	}
+
+	if err := dest.Parent().MkdirAll(); err != nil {
+		return err
+	}
+
+	if err := dest.Symlink(target); err != nil {
+		return err
+	}
+
+	if !c.opts.PreserveTimes {
+		return nil
+	}
+
+	info, err := os.Lstat(src.String())
+	if err != nil {
+		return wrapError(err)
+	}
+	return c.copyTimes(info, dest)
+}
+
+// link creates dest as a hard link to a file already copied
+func (c *copier) link(copied, dest Path) error {
+	if dest.DirExists() {
+		return newError("copy", dest, ErrPathIsDirectory)
+	}
+
+	if err := os.Remove(dest.String()); err != nil && !os.IsNotExist(err) {
+		return wrapError(err)
+	}
+
+	if err := dest.Parent().MkdirAll(); err != nil {
+		return err
+	}
+
+	return copied.Link(dest)
+}
@@ -0,0 +1,143 @@
+package fs
+
+import (
+	"os"
+	"time"
+)
+
+// ReadDirOptions configures the reading done by ReadDirEntries
+type ReadDirOptions struct {
+	// Sort determines the order of the entries
+	Sort SortOrder
+
+	// Stat makes the information of every entry be fetched while reading the
+	// directory, instead of when it is first requested.
+	Stat bool
+}
+
+// DirEntry is an entry read from a directory. The information about the entry
+// is only fetched from the filesystem when it is requested.
+type DirEntry struct {
+	path  Path
+	entry os.DirEntry
+	info  os.FileInfo
+	depth int
+}
+
+// ReadDirEntries reads the directory and returns its entries in the given order
+func (p Path) ReadDirEntries(opts ReadDirOptions) ([]*DirEntry, error) {
+	if _, err := statDir("readdir", p); err != nil {
+		return nil, err
+	}
+
+	entries, err := readDirEntries(p, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		e := newDirEntry(p, entry, 1)
+
+		if opts.Stat {
+			if _, err := e.Info(); err != nil {
+				// entries removed while reading are ignored
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+		}
+
+		result = append(result, e)
+	}
+
+	return result, nil
+}
+
+// newDirEntry creates an entry for a os.DirEntry read from the directory dir
+func newDirEntry(dir Path, entry os.DirEntry, depth int) *DirEntry {
+	e := &DirEntry{
+		path:  dir.Join(entry.Name()),
+		entry: entry,
+		depth: depth,
+	}
+
+	// entries sorted by their information already have it
+	if stated, ok := entry.(*infoEntry); ok {
+		e.info = stated.info
+	}
+	return e
+}
+
+// Path returns the full path of the entry
+func (e *DirEntry) Path() Path {
+	return e.path
+}
+
+// Name returns the name of the entry, i.e. the last element of its path
+func (e *DirEntry) Name() string {
+	return e.entry.Name()
+}
+
+// Depth returns the depth of the entry relative to the walked path, where the
+// direct children have depth 1.
+func (e *DirEntry) Depth() int {
+	return e.depth
+}
+
+// IsDir returns true when the entry is a directory
+func (e *DirEntry) IsDir() bool {
+	return e.Type().IsDir()
+}
+
+// Type returns the type bits of the entry, without fetching its information
+// when possible.
+func (e *DirEntry) Type() os.FileMode {
+	if e.info != nil {
+		return e.info.Mode().Type()
+	}
+	return e.entry.Type()
+}
+
+// Info returns the information of the entry, fetching it on the first call
+func (e *DirEntry) Info() (os.FileInfo, error) {
+	if e.info != nil {
+		return e.info, nil
+	}
+
+	info, err := e.entry.Info()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	e.info = info
+	return info, nil
+}
+
+// Size returns the length in bytes of the entry, or zero when its information
+// can't be fetched.
+func (e *DirEntry) Size() int64 {
+	if info, err := e.Info(); err == nil {
+		return info.Size()
+	}
+	return 0
+}
+
+// Mode returns the mode bits of the entry, or just its type bits when its
+// information can't be fetched.
+func (e *DirEntry) Mode() os.FileMode {
+	if info, err := e.Info(); err == nil {
+		return info.Mode()
+	}
+	return e.Type()
+}
+
+// ModTime returns the modification time of the entry, or the zero time when its
+// information can't be fetched.
+func (e *DirEntry) ModTime() time.Time {
+	if info, err := e.Info(); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
@@ -0,0 +1,97 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestReadDirEntries(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		files := []struct {
+			name    string
+			size    int
+			modTime time.Time
+		}{
+			{name: "file10.txt", size: 1, modTime: time.Unix(3000, 0)},
+			{name: "file2.txt", size: 3, modTime: time.Unix(1000, 0)},
+			{name: "File1.txt", size: 2, modTime: time.Unix(2000, 0)},
+			{name: "file02.txt", size: 4, modTime: time.Unix(4000, 0)},
+		}
+
+		for _, f := range files {
+			path := root.Join(f.name)
+
+			file, err := path.Create()
+			if err != nil {
+				t.Errorf("Error creating file: %v", err)
+				return
+			}
+			_, _ = file.Write([]byte(strings.Repeat("x", f.size)))
+			file.Close()
+
+			if err := os.Chtimes(path.String(), f.modTime, f.modTime); err != nil {
+				t.Errorf("Error changing times: %v", err)
+				return
+			}
+		}
+
+		tests := []struct {
+			opts     fs.ReadDirOptions
+			expected []string
+		}{
+			{
+				opts:     fs.ReadDirOptions{Sort: fs.SortByName},
+				expected: []string{"File1.txt", "file02.txt", "file10.txt", "file2.txt"},
+			},
+			{
+				opts:     fs.ReadDirOptions{Sort: fs.SortNatural},
+				expected: []string{"File1.txt", "file2.txt", "file02.txt", "file10.txt"},
+			},
+			{
+				opts:     fs.ReadDirOptions{Sort: fs.SortBySize, Stat: true},
+				expected: []string{"file10.txt", "File1.txt", "file2.txt", "file02.txt"},
+			},
+			{
+				opts:     fs.ReadDirOptions{Sort: fs.SortByModTime},
+				expected: []string{"file2.txt", "File1.txt", "file10.txt", "file02.txt"},
+			},
+		}
+
+		for i, test := range tests {
+			entries, err := root.ReadDirEntries(test.opts)
+			if err != nil {
+				t.Errorf("Case %d, error reading entries: %v", i, err)
+				continue
+			}
+
+			var received []string
+			for _, e := range entries {
+				received = append(received, e.Name())
+
+				if e.Path() != root.Join(e.Name()) {
+					t.Errorf("Case %d, unexpected path '%s' for entry '%s'", i, e.Path(), e.Name())
+				}
+
+				if e.IsDir() || !e.Mode().IsRegular() || e.Size() == 0 || e.ModTime().IsZero() {
+					t.Errorf("Case %d, unexpected information for entry '%s'", i, e.Name())
+				}
+			}
+
+			if !reflect.DeepEqual(received, test.expected) {
+				t.Errorf("Case %d, error testing read dir entries: expected '%v', received '%v'", i, test.expected, received)
+			}
+		}
+
+		if _, err := root.Join("file2.txt").ReadDirEntries(fs.ReadDirOptions{}); !errors.Is(err, fs.ErrDirDoesNotExist) {
+			t.Errorf("Error testing read dir entries on a file: expected '%v', received '%v'", fs.ErrDirDoesNotExist, err)
+		}
+	})
+}
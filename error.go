@@ -2,17 +2,95 @@ package fs
 
 import (
 	"errors"
+	"os"
 )
 
+// Error records an error and the operation and path that caused it. It wraps
+// either one of the errors of this package or the error returned by the
+// operating system, and can be inspected with errors.Is and errors.As.
+//
+// Missing paths match ErrNotFound and os.ErrNotExist, paths that can't be
+// accessed match ErrPermissionDenied and os.ErrPermission, and paths already
+// existing match ErrPathExists and os.ErrExist, whatever the error wrapped is.
+// Existing paths that aren't a directory where one is required match
+// ErrNotDirectory and, as the directory requested doesn't exist, ErrDirDoesNotExist,
+// but not ErrNotFound.
+type Error struct {
+	Op   string
+	Path Path
+	Err  error
+}
+
+// Error returns the description of the error
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Op + ": " + e.Err.Error()
+	}
+	return e.Op + " " + e.Path.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches the target, mapping the not found,
+// permission and existence errors of the operating system to the ones of this package.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound, os.ErrNotExist:
+		return isNotFound(e.Err)
+	case ErrPermissionDenied, os.ErrPermission:
+		return errors.Is(e.Err, os.ErrPermission)
+	case ErrPathExists, os.ErrExist:
+		return e.Err == ErrPathExists || errors.Is(e.Err, os.ErrExist)
+	case ErrDirDoesNotExist:
+		return e.Err == ErrNotDirectory
+	}
+	return false
+}
+
+// newError creates a error for the given operation and path
+func newError(op string, p Path, err error) *Error {
+	return &Error{Op: op, Path: p, Err: err}
+}
+
+// wrapError converts the errors returned by the os package to the Error type,
+// keeping their operation and path. Other errors are returned as they are.
+func wrapError(err error) error {
+	switch e := err.(type) {
+	case *os.PathError:
+		return newError(e.Op, Path(e.Path), e.Err)
+	case *os.LinkError:
+		return newError(e.Op, Path(e.Old), e.Err)
+	}
+	return err
+}
+
+// isNotFound returns true when the error indicates a missing path
+func isNotFound(err error) bool {
+	switch err {
+	case ErrNotFound, ErrFileDoesNotExist, ErrDirDoesNotExist:
+		return true
+	}
+	return errors.Is(err, os.ErrNotExist)
+}
+
 // ErrDirDoesNotExist is a error indicating that a given directory does not exists.
 var ErrDirDoesNotExist = errors.New("directory does not exist")
 
+// ErrNotDirectory is a error indicating that a given path exists, but is not a directory.
+var ErrNotDirectory = errors.New("Path is not a directory")
+
 // ErrFileDoesNotExist is a error indicating that a given file does not exists.
 var ErrFileDoesNotExist = errors.New("file does not exist")
 
 // ErrNotFound is a error indicating that a given path does not exists.
 var ErrNotFound = errors.New("Path not found")
 
+// ErrPermissionDenied is a error indicating that a given path can't be accessed.
+var ErrPermissionDenied = errors.New("Permission denied")
+
 // ErrPathIsEmpty is a error indicating that a given path is empty like ''.
 var ErrPathIsEmpty = errors.New("Path is empty")
 
@@ -27,3 +105,43 @@ var ErrOneDirectoryOtherFile = errors.New("One is a directory and the other is a
 
 // ErrFilesNotEquals is a error indicating that the files source and destionation aren't equals.
 var ErrFilesNotEquals = errors.New("Source and destination files aren't equals")
+
+// ErrStopWalk is a error that can be returned by a walker function to stop the walking routine
+// without reporting an error to the caller.
+var ErrStopWalk = errors.New("Stop walking")
+
+// ErrCannotResume is a error indicating that a walk can't be resumed from the given path.
+var ErrCannotResume = errors.New("Walk can only be resumed from a path inside the walked directory and sorted by name")
+
+// ErrProtectedPath is a error indicating that a given path is protected against removal.
+var ErrProtectedPath = errors.New("Path is protected")
+
+// ErrOutsideRoot is a error indicating that a given path is not inside the expected root directory.
+var ErrOutsideRoot = errors.New("Path is outside the root directory")
+
+// ErrPathExists is a error indicating that a given path already exists.
+var ErrPathExists = errors.New("Path already exists")
+
+// ErrNotSupported is a error indicating that a given operation is not supported on this platform.
+var ErrNotSupported = errors.New("Operation not supported")
+
+// ErrNoXattr is a error indicating that a given extended attribute does not exist.
+var ErrNoXattr = errors.New("Extended attribute not found")
+
+// ErrInvalidMode is a error indicating that a given mode string can't be parsed.
+var ErrInvalidMode = errors.New("Invalid mode")
+
+// ErrInvalidExpansion is a error indicating that a given path has a malformed variable expansion.
+var ErrInvalidExpansion = errors.New("Invalid expansion")
+
+// ErrInvalidName is a error indicating that a given file name has characters not allowed.
+var ErrInvalidName = errors.New("Invalid file name")
+
+// ErrNameTooLong is a error indicating that a given file name is longer than allowed.
+var ErrNameTooLong = errors.New("File name too long")
+
+// ErrPathTooLong is a error indicating that a given path is longer than allowed.
+var ErrPathTooLong = errors.New("Path too long")
+
+// ErrIsSymlink is a error indicating that a given path is a symbolic link where one is not allowed.
+var ErrIsSymlink = errors.New("Path is a symbolic link")
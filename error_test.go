@@ -0,0 +1,80 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestErrorIs(t *testing.T) {
+	tests := []struct {
+		err      error
+		target   error
+		expected bool
+	}{
+		{err: &fs.Error{Op: "open", Path: "a", Err: fs.ErrFileDoesNotExist}, target: fs.ErrFileDoesNotExist, expected: true},
+		{err: &fs.Error{Op: "open", Path: "a", Err: fs.ErrFileDoesNotExist}, target: fs.ErrNotFound, expected: true},
+		{err: &fs.Error{Op: "open", Path: "a", Err: fs.ErrFileDoesNotExist}, target: os.ErrNotExist, expected: true},
+		{err: &fs.Error{Op: "open", Path: "a", Err: fs.ErrFileDoesNotExist}, target: fs.ErrPermissionDenied, expected: false},
+		{err: &fs.Error{Op: "open", Path: "a", Err: fs.ErrDirDoesNotExist}, target: fs.ErrFileDoesNotExist, expected: false},
+		{err: &fs.Error{Op: "mkdir", Path: "a", Err: syscall.ENOENT}, target: fs.ErrNotFound, expected: true},
+		{err: &fs.Error{Op: "mkdir", Path: "a", Err: syscall.ENOENT}, target: os.ErrNotExist, expected: true},
+		{err: &fs.Error{Op: "mkdir", Path: "a", Err: syscall.EACCES}, target: fs.ErrPermissionDenied, expected: true},
+		{err: &fs.Error{Op: "mkdir", Path: "a", Err: syscall.EPERM}, target: os.ErrPermission, expected: true},
+		{err: &fs.Error{Op: "mkdir", Path: "a", Err: syscall.EACCES}, target: fs.ErrNotFound, expected: false},
+		{err: &fs.Error{Op: "open", Path: "a", Err: fs.ErrPathIsEmpty}, target: fs.ErrNotFound, expected: false},
+		{err: &fs.Error{Op: "walk", Path: "a", Err: fs.ErrNotDirectory}, target: fs.ErrNotDirectory, expected: true},
+		{err: &fs.Error{Op: "walk", Path: "a", Err: fs.ErrNotDirectory}, target: fs.ErrDirDoesNotExist, expected: true},
+		{err: &fs.Error{Op: "walk", Path: "a", Err: fs.ErrNotDirectory}, target: fs.ErrNotFound, expected: false},
+		{err: &fs.Error{Op: "walk", Path: "a", Err: fs.ErrNotDirectory}, target: os.ErrNotExist, expected: false},
+	}
+
+	for i, test := range tests {
+		if received := errors.Is(test.err, test.target); received != test.expected {
+			t.Errorf("Case %d, error testing errors.Is(%v, %v): expected '%v', received '%v'", i, test.err, test.target, test.expected, received)
+		}
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		missing := root.Join("missing.txt")
+
+		tests := []struct {
+			err  error
+			op   string
+			path fs.Path
+		}{
+			{err: errorOf(missing.Open()), op: "open", path: missing},
+			{err: errorOf(missing.ReadAll()), op: "read", path: missing},
+			{err: errorOf(missing.ReadDir()), op: "readdir", path: missing},
+			{err: missing.CopyTo(root.Join("copy.txt")), op: "copy", path: missing},
+			{err: missing.Walk(fs.WalkBoth, nil), op: "walk", path: missing},
+		}
+
+		for i, test := range tests {
+			var e *fs.Error
+			if !errors.As(test.err, &e) {
+				t.Errorf("Case %d, error '%v' should be a *fs.Error", i, test.err)
+				continue
+			}
+
+			if e.Op != test.op || e.Path != test.path {
+				t.Errorf("Case %d, error testing error context: expected '%s %s', received '%s %s'", i, test.op, test.path, e.Op, e.Path)
+			}
+
+			if !errors.Is(test.err, fs.ErrNotFound) || !errors.Is(test.err, os.ErrNotExist) {
+				t.Errorf("Case %d, error '%v' should be a not found error", i, test.err)
+			}
+		}
+	})
+}
+
+// errorOf returns the error of a call returning a value and an error
+func errorOf(_ interface{}, err error) error {
+	return err
+}
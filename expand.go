@@ -0,0 +1,219 @@
+package fs
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandOptions configures the lookups done by ExpandWithOptions and
+// ContractWithOptions. Nil functions use the environment of the process and
+// the user database of the system.
+type ExpandOptions struct {
+	// LookupEnv returns the value of a environment variable and whether it is set
+	LookupEnv func(key string) (string, bool)
+
+	// LookupHome returns the home directory of a user, or of the current user
+	// when the name is empty.
+	LookupHome func(username string) (string, error)
+}
+
+// Expand replaces a leading `~` or `~user` with the home directory of the
+// user, and the `$VAR`, `${VAR}`, `${VAR:-default}` and `${VAR-default}`
+// variables with their values in the environment. As in the shell, undefined
+// variables are replaced by an empty string, and the default of `:-` is also
+// used when the variable is empty.
+func (p Path) Expand() (Path, error) {
+	return p.ExpandWithOptions(ExpandOptions{})
+}
+
+// ExpandWithOptions works like Expand, with the lookups configured by the options
+func (p Path) ExpandWithOptions(opts ExpandOptions) (Path, error) {
+	opts.defaults()
+
+	// the variables are only expanded after the home directory, so the home
+	// directory is kept as it is, even when it has a `$`
+	home, rest, err := expandTilde(p.String(), opts)
+	if err != nil {
+		return "", newError("expand", p, err)
+	}
+
+	rest, err = expandVars(rest, opts)
+	if err != nil {
+		return "", newError("expand", p, err)
+	}
+
+	return Path(home + rest), nil
+}
+
+// Contract replaces the home directory of the current user at the start of
+// the path with `~`, for display.
+func (p Path) Contract() Path {
+	return p.ContractWithOptions(ExpandOptions{})
+}
+
+// ContractWithOptions works like Contract, with the lookups configured by the options
+func (p Path) ContractWithOptions(opts ExpandOptions) Path {
+	opts.defaults()
+
+	home, err := opts.LookupHome("")
+	if err != nil || home == "" || !filepath.IsAbs(p.String()) {
+		return p
+	}
+
+	if p.Clean() == Path(home).Clean() {
+		return "~"
+	}
+
+	rel, err := p.Rebase(Path(home), "~")
+	if err != nil {
+		return p
+	}
+	return rel
+}
+
+// defaults sets the lookups not given in the options
+func (opts *ExpandOptions) defaults() {
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+
+	if opts.LookupHome == nil {
+		lookupEnv := opts.LookupEnv
+		opts.LookupHome = func(username string) (string, error) {
+			if username == "" {
+				if home, ok := lookupEnv("HOME"); ok && home != "" {
+					return home, nil
+				}
+				return os.UserHomeDir()
+			}
+
+			u, err := user.Lookup(username)
+			if err != nil {
+				return "", err
+			}
+			return u.HomeDir, nil
+		}
+	}
+}
+
+// expandTilde returns the home directory replacing a leading `~` or `~user`,
+// if any, and the rest of the path.
+func expandTilde(path string, opts ExpandOptions) (string, string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return "", path, nil
+	}
+
+	name, rest := path[1:], ""
+	if i := strings.IndexAny(name, `/`+string(filepath.Separator)); i >= 0 {
+		name, rest = name[:i], name[i:]
+	}
+
+	home, err := opts.LookupHome(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	return home, rest, nil
+}
+
+// expandVars replaces the variables in the path with their values
+func expandVars(path string, opts ExpandOptions) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		if path[i] != '$' || i+1 == len(path) {
+			b.WriteByte(path[i])
+			continue
+		}
+
+		// ${VAR}, with an optional default
+		if path[i+1] == '{' {
+			end := closingBrace(path, i+2)
+			if end < 0 {
+				return "", ErrInvalidExpansion
+			}
+
+			value, err := expandBraces(path[i+2:end], opts)
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteString(value)
+			i = end
+			continue
+		}
+
+		// $VAR
+		n := nameLength(path[i+1:])
+		if n == 0 {
+			b.WriteByte(path[i])
+			continue
+		}
+
+		value, _ := opts.LookupEnv(path[i+1 : i+1+n])
+		b.WriteString(value)
+		i += n
+	}
+
+	return b.String(), nil
+}
+
+// expandBraces returns the value of the content of a `${...}` expansion
+func expandBraces(expr string, opts ExpandOptions) (string, error) {
+	n := nameLength(expr)
+	if n == 0 {
+		return "", ErrInvalidExpansion
+	}
+
+	name, rest := expr[:n], expr[n:]
+	value, ok := opts.LookupEnv(name)
+
+	switch {
+	case rest == "":
+		return value, nil
+	case strings.HasPrefix(rest, ":-"):
+		if value != "" {
+			return value, nil
+		}
+		return expandVars(rest[2:], opts)
+	case strings.HasPrefix(rest, "-"):
+		if ok {
+			return value, nil
+		}
+		return expandVars(rest[1:], opts)
+	}
+
+	return "", ErrInvalidExpansion
+}
+
+// closingBrace returns the index of the brace closing the expansion starting
+// at the given index, taking nested expansions into account, or -1.
+func closingBrace(path string, start int) int {
+	depth := 1
+	for i := start; i < len(path); i++ {
+		switch {
+		case path[i] == '$' && i+1 < len(path) && path[i+1] == '{':
+			depth++
+			i++
+		case path[i] == '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// nameLength returns the length of the variable name at the start of s
+func nameLength(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && isDigit(c) {
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
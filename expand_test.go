@@ -0,0 +1,108 @@
+package fs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestExpand(t *testing.T) {
+	env := map[string]string{
+		"HOME":  "/home/alice",
+		"ENV":   "prod",
+		"EMPTY": "",
+		"NAME":  "app",
+	}
+
+	opts := fs.ExpandOptions{
+		LookupEnv: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+		LookupHome: func(username string) (string, error) {
+			switch username {
+			case "":
+				return env["HOME"], nil
+			case "bob":
+				return "/home/bob", nil
+			case "dollar":
+				return "/home/a$NAME", nil
+			}
+			return "", fs.ErrNotFound
+		},
+	}
+
+	tests := []struct {
+		path     fs.Path
+		expected fs.Path
+		err      error
+	}{
+		{path: "~/data/$ENV/cache", expected: "/home/alice/data/prod/cache"},
+		{path: "~", expected: "/home/alice"},
+		{path: "~bob/data", expected: "/home/bob/data"},
+		{path: "~bob", expected: "/home/bob"},
+		{path: "~dollar/$NAME", expected: "/home/a$NAME/app"},
+		{path: "/data/~/x", expected: "/data/~/x"},
+		{path: "/var/${NAME}/${ENV}.log", expected: "/var/app/prod.log"},
+		{path: "/var/${MISSING:-default}", expected: "/var/default"},
+		{path: "/var/${EMPTY:-default}", expected: "/var/default"},
+		{path: "/var/${EMPTY-default}", expected: "/var/"},
+		{path: "/var/${MISSING-default}", expected: "/var/default"},
+		{path: "/var/${MISSING:-${NAME}/x}", expected: "/var/app/x"},
+		{path: "/var/${ENV:-dev}", expected: "/var/prod"},
+		{path: "/var/$MISSING/x", expected: "/var//x"},
+		{path: "/var/$NAME.d", expected: "/var/app.d"},
+		{path: "/var/$/x$", expected: "/var/$/x$"},
+		{path: "/var/${NAME", err: fs.ErrInvalidExpansion},
+		{path: "/var/${}", err: fs.ErrInvalidExpansion},
+		{path: "/var/${NAME:x}", err: fs.ErrInvalidExpansion},
+		{path: "~carol/data", err: fs.ErrNotFound},
+	}
+
+	for i, test := range tests {
+		received, err := test.path.ExpandWithOptions(opts)
+		if !errors.Is(err, test.err) || received != test.expected {
+			t.Errorf("Case %d, error testing expand of '%s': expected '%s' (%v), received '%s' (%v)", i, test.path, test.expected, test.err, received, err)
+		}
+	}
+
+	// the home directory of the environment is kept as it is too
+	env["HOME"] = "/home/a$b"
+	opts.LookupHome = nil
+	if received, err := fs.Path("~/x").ExpandWithOptions(opts); err != nil || received != "/home/a$b/x" {
+		t.Errorf("Error testing expand of a home with '$': expected '/home/a$b/x', received '%s' (%v)", received, err)
+	}
+}
+
+func TestContract(t *testing.T) {
+	opts := fs.ExpandOptions{
+		LookupHome: func(username string) (string, error) {
+			return "/home/alice", nil
+		},
+	}
+
+	tests := []struct {
+		path     fs.Path
+		expected fs.Path
+	}{
+		{path: "/home/alice/data/cache", expected: "~/data/cache"},
+		{path: "/home/alice", expected: "~"},
+		{path: "/home/alice/", expected: "~"},
+		{path: "/home/alicex/data", expected: "/home/alicex/data"},
+		{path: "/var/data", expected: "/var/data"},
+		{path: "data", expected: "data"},
+	}
+
+	for i, test := range tests {
+		if received := test.path.ContractWithOptions(opts); received != test.expected {
+			t.Errorf("Case %d, error testing contract of '%s': expected '%s', received '%s'", i, test.path, test.expected, received)
+		}
+
+		if test.expected[0] == '~' {
+			if expanded, err := test.expected.ExpandWithOptions(opts); err != nil || expanded != test.path.Clean() {
+				t.Errorf("Case %d, error testing expand of contracted '%s': expected '%s', received '%s' (%v)", i, test.expected, test.path.Clean(), expanded, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package fs
+
+import "os"
+
+// SetMountsFile replaces the file listing the mount points, returning a
+// function restoring the previous one.
+func SetMountsFile(name string) func() {
+	previous := mountsFile
+	mountsFile = name
+	return func() { mountsFile = previous }
+}
+
+// SetLstat replaces the function used to look the paths up without following links,
+// returning a function restoring the previous one.
+func SetLstat(f func(name string) (os.FileInfo, error)) func() {
+	previous := lstat
+	lstat = f
+	return func() { lstat = previous }
+}
@@ -0,0 +1,111 @@
+package fs
+
+import (
+	"strings"
+)
+
+// CompoundExts are the extensions made of more than one part, which are
+// handled as a single extension by Exts, Stem, WithExt and WithoutExt. It
+// can be changed to recognize other extensions, before being used.
+var CompoundExts = []string{
+	".tar.gz",
+	".tar.bz2",
+	".tar.xz",
+	".tar.zst",
+	".tar.lz",
+	".tar.lz4",
+	".tar.lzma",
+	".tar.Z",
+}
+
+// Exts returns the parts of the extension of the path, including the "."
+// characters, which are more than one for the compound extensions, like
+// ".tar" and ".gz" for "archive.tar.gz". Names starting with a "." without
+// any other one, like ".bashrc", have no extension.
+func (p Path) Exts() []string {
+	ext := p.fullExt()
+	if ext == "" {
+		return nil
+	}
+
+	exts := strings.SplitAfter(ext[1:], ".")
+	for i := range exts {
+		exts[i] = "." + strings.TrimSuffix(exts[i], ".")
+	}
+	return exts
+}
+
+// Stem returns the name of the last element of the path without its extensions
+func (p Path) Stem() string {
+	name := p.Basename()
+	return name[:len(name)-len(p.fullExt())]
+}
+
+// WithExt returns the path with its extensions replaced by the given one,
+// which may be given with or without the leading "." character.
+func (p Path) WithExt(ext string) Path {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return p.WithBasename(p.Stem() + ext)
+}
+
+// WithoutExt returns the path without its extensions
+func (p Path) WithoutExt() Path {
+	return p.WithExt("")
+}
+
+// WithBasename returns the path with its last element replaced by the given name
+func (p Path) WithBasename(name string) Path {
+	return p.Clean().Parent().Join(name)
+}
+
+// HasExt returns true when the extension of the path, either the last part or
+// the compound one, is one of the given extensions, ignoring the case. The
+// extensions may be given with or without the leading "." character.
+func (p Path) HasExt(exts ...string) bool {
+	parts := p.Exts()
+	if len(parts) == 0 {
+		return false
+	}
+
+	full, last := strings.Join(parts, ""), parts[len(parts)-1]
+
+	for _, ext := range exts {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if strings.EqualFold(ext, full) || strings.EqualFold(ext, last) {
+			return true
+		}
+	}
+	return false
+}
+
+// fullExt returns the extension of the path, which is a compound one when
+// registered in CompoundExts.
+func (p Path) fullExt() string {
+	name := p.Basename()
+
+	// the leading "." of hidden files doesn't start a extension
+	hidden := strings.HasPrefix(name, ".")
+	if hidden {
+		name = name[1:]
+	}
+
+	// the longest of the compound extensions matching
+	compound := 0
+	for _, ext := range CompoundExts {
+		if len(ext) > compound && len(name) > len(ext) && strings.EqualFold(name[len(name)-len(ext):], ext) {
+			compound = len(ext)
+		}
+	}
+	if compound > 0 {
+		return name[len(name)-compound:]
+	}
+
+	if i := strings.LastIndexByte(name, '.'); i >= 0 && (i > 0 || !hidden) {
+		return name[i:]
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package fs_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestExts(t *testing.T) {
+	tests := []struct {
+		path    fs.Path
+		exts    []string
+		stem    string
+		without fs.Path
+		withZip fs.Path
+	}{
+		{path: "/a/archive.tar.gz", exts: []string{".tar", ".gz"}, stem: "archive", without: "/a/archive", withZip: "/a/archive.zip"},
+		{path: "/a/ARCHIVE.TAR.BZ2", exts: []string{".TAR", ".BZ2"}, stem: "ARCHIVE", without: "/a/ARCHIVE", withZip: "/a/ARCHIVE.zip"},
+		{path: "/a/report.v2.pdf", exts: []string{".pdf"}, stem: "report.v2", without: "/a/report.v2", withZip: "/a/report.v2.zip"},
+		{path: "/a/data.gz", exts: []string{".gz"}, stem: "data", without: "/a/data", withZip: "/a/data.zip"},
+		{path: "file.txt", exts: []string{".txt"}, stem: "file", without: "file", withZip: "file.zip"},
+		{path: "/a/README", exts: nil, stem: "README", without: "/a/README", withZip: "/a/README.zip"},
+		{path: "/a/.bashrc", exts: nil, stem: ".bashrc", without: "/a/.bashrc", withZip: "/a/.bashrc.zip"},
+		{path: "/a/.config.json", exts: []string{".json"}, stem: ".config", without: "/a/.config", withZip: "/a/.config.zip"},
+		{path: "/a/.tar.gz", exts: []string{".gz"}, stem: ".tar", without: "/a/.tar", withZip: "/a/.tar.zip"},
+		{path: "/a/b.c/file", exts: nil, stem: "file", without: "/a/b.c/file", withZip: "/a/b.c/file.zip"},
+		{path: "/a/dir.d/", exts: []string{".d"}, stem: "dir", without: "/a/dir", withZip: "/a/dir.zip"},
+	}
+
+	for i, test := range tests {
+		if received := test.path.Exts(); !reflect.DeepEqual(received, test.exts) {
+			t.Errorf("Case %d, error testing exts of '%s': expected '%v', received '%v'", i, test.path, test.exts, received)
+		}
+
+		if received := test.path.Stem(); received != test.stem {
+			t.Errorf("Case %d, error testing stem of '%s': expected '%s', received '%s'", i, test.path, test.stem, received)
+		}
+
+		if received := test.path.WithoutExt(); received != test.without {
+			t.Errorf("Case %d, error testing without ext of '%s': expected '%s', received '%s'", i, test.path, test.without, received)
+		}
+
+		for _, ext := range []string{".zip", "zip"} {
+			if received := test.path.WithExt(ext); received != test.withZip {
+				t.Errorf("Case %d, error testing with ext '%s' of '%s': expected '%s', received '%s'", i, ext, test.path, test.withZip, received)
+			}
+		}
+	}
+}
+
+func TestWithBasename(t *testing.T) {
+	tests := []struct {
+		path     fs.Path
+		name     string
+		expected fs.Path
+	}{
+		{path: "/a/b/file.txt", name: "other.md", expected: "/a/b/other.md"},
+		{path: "/a/b/", name: "c", expected: "/a/c"},
+		{path: "file.txt", name: "other.md", expected: "other.md"},
+		{path: "/file.txt", name: "other.md", expected: "/other.md"},
+	}
+
+	for i, test := range tests {
+		if received := test.path.WithBasename(test.name); received != test.expected {
+			t.Errorf("Case %d, error testing with basename: expected '%s', received '%s'", i, test.expected, received)
+		}
+	}
+}
+
+func TestHasExt(t *testing.T) {
+	tests := []struct {
+		path     fs.Path
+		exts     []string
+		expected bool
+	}{
+		{path: "archive.tar.gz", exts: []string{".tar.gz"}, expected: true},
+		{path: "archive.tar.gz", exts: []string{"gz"}, expected: true},
+		{path: "archive.tar.gz", exts: []string{".tar"}, expected: false},
+		{path: "archive.TAR.GZ", exts: []string{".zip", ".tar.gz"}, expected: true},
+		{path: "photo.JPG", exts: []string{".jpg", ".jpeg"}, expected: true},
+		{path: "photo.png", exts: []string{".jpg", ".jpeg"}, expected: false},
+		{path: "README", exts: []string{""}, expected: false},
+		{path: ".bashrc", exts: []string{".bashrc"}, expected: false},
+		{path: "file.txt", exts: nil, expected: false},
+	}
+
+	for i, test := range tests {
+		if received := test.path.HasExt(test.exts...); received != test.expected {
+			t.Errorf("Case %d, error testing '%s' has ext '%v': expected '%v', received '%v'", i, test.path, test.exts, test.expected, received)
+		}
+	}
+}
+
+func TestCompoundExts(t *testing.T) {
+	defer func(exts []string) { fs.CompoundExts = exts }(fs.CompoundExts)
+	fs.CompoundExts = append(fs.CompoundExts, ".pkg.tar.zst")
+
+	path := fs.Path("/var/cache/pacman/linux-6.1.pkg.tar.zst")
+
+	if received := path.Stem(); received != "linux-6.1" {
+		t.Errorf("Error testing registered compound ext: expected 'linux-6.1', received '%s'", received)
+	}
+
+	if received := path.Exts(); !reflect.DeepEqual(received, []string{".pkg", ".tar", ".zst"}) {
+		t.Errorf("Error testing registered compound ext: received '%v'", received)
+	}
+}
@@ -0,0 +1,244 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// Filesystem creates files and directories with configurable modes. The zero
+// value uses the default modes, 0644 for files and 0755 for directories.
+//
+// It always works on the filesystem of the operating system: there is no
+// in-memory backend, so operations like the extended attributes of Path have
+// no in-memory implementation either.
+type Filesystem struct {
+	// FileMode is the mode of the files created
+	FileMode os.FileMode
+
+	// DirMode is the mode of the directories created by MkdirAll
+	DirMode os.FileMode
+
+	// ParentDirMode is the mode of the parent directories created along with
+	// files and directories.
+	ParentDirMode os.FileMode
+
+	// IgnoreUmask makes the created files and directories have exactly the
+	// configured modes, instead of having the bits in the umask removed.
+	IgnoreUmask bool
+}
+
+const (
+	// defaultUniquePattern is the suffix of the names chosen by CreateUnique
+	defaultUniquePattern = " (%d)"
+
+	// maxUniqueAttempts is the number of suffixes tried by CreateUnique
+	maxUniqueAttempts = 10000
+)
+
+// DefaultFilesystem is the Filesystem used by the methods of Path
+var DefaultFilesystem = &Filesystem{}
+
+// Create open the specified file for writing, creating a new file and its
+// parents if necessary. If the file already exists, it is overridden.
+func (fsys *Filesystem) Create(p Path) (*os.File, error) {
+	return fsys.open(p, createFileFlag, fsys.fileMode())
+}
+
+// Append works like create, but instead of discarding the content of an existing file,
+// it just appends the new data at the end of the file.
+func (fsys *Filesystem) Append(p Path) (*os.File, error) {
+	return fsys.open(p, appendFileFlag, fsys.fileMode())
+}
+
+// CreateUnique creates a new file for writing at the path or, when it is taken,
+// at the first free path with a numbered suffix. The suffix is formatted by
+// the pattern, which must have a verb for the number, like the default
+// " (%d)", and is added before the extensions, so "report.pdf" is followed by
+// "report (1).pdf". The check for existence and the creation are atomic. It
+// returns the file and the path chosen.
+func (fsys *Filesystem) CreateUnique(p Path, pattern string) (*os.File, Path, error) {
+	if pattern == "" {
+		pattern = defaultUniquePattern
+	}
+
+	// the pattern must result in different names, on the same directory
+	first, second := fmt.Sprintf(pattern, 1), fmt.Sprintf(pattern, 2)
+	if first == second || strings.Contains(first, "%!") || strings.ContainsAny(first, `/\`) {
+		return nil, "", newError("create", p, ErrInvalidName)
+	}
+
+	ext := strings.Join(p.Exts(), "")
+	for i := 0; i <= maxUniqueAttempts; i++ {
+		path := p
+		if i > 0 {
+			path = p.WithBasename(p.Stem() + fmt.Sprintf(pattern, i) + ext)
+		}
+
+		file, err := fsys.open(path, newFileFlag, fsys.fileMode())
+		if err == nil {
+			return file, path, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) && !errors.Is(err, ErrPathIsDirectory) {
+			return nil, "", err
+		}
+	}
+
+	return nil, "", newError("create", p, ErrPathExists)
+}
+
+// MkdirAll creates the directory with DirMode, along with its parents that
+// doesn't exists with ParentDirMode. The modes of existing directories are not
+// changed.
+func (fsys *Filesystem) MkdirAll(p Path) error {
+	return fsys.mkdirAll(p, fsys.dirMode())
+}
+
+// fileMode returns the mode of the files created
+func (fsys *Filesystem) fileMode() os.FileMode {
+	if fsys.FileMode == 0 {
+		return defaultFileMode
+	}
+	return fsys.FileMode
+}
+
+// dirMode returns the mode of the directories created
+func (fsys *Filesystem) dirMode() os.FileMode {
+	if fsys.DirMode == 0 {
+		return defaultDirMode
+	}
+	return fsys.DirMode
+}
+
+// parentDirMode returns the mode of the parent directories created
+func (fsys *Filesystem) parentDirMode() os.FileMode {
+	if fsys.ParentDirMode == 0 {
+		return defaultDirMode
+	}
+	return fsys.ParentDirMode
+}
+
+// OpenFile opens the file with the given flags, like os.OpenFile, with the
+// behavior configured by the options. Unless disabled by the options, the
+// missing parents of a file being created are created as well.
+func (fsys *Filesystem) OpenFile(p Path, flag int, opts OpenOptions) (*os.File, error) {
+	mode := opts.Mode
+	if mode == 0 {
+		mode = fsys.fileMode()
+	}
+	return fsys.openFile(p, flag, mode, opts)
+}
+
+// open opens the file with the given flags, creating its parents when the file
+// is created and they doesn't exist.
+func (fsys *Filesystem) open(p Path, flag int, mode os.FileMode) (*os.File, error) {
+	return fsys.openFile(p, flag, mode, OpenOptions{})
+}
+
+// openFile opens the file with the given flags and mode, as configured by the
+// options. The file is opened first and the errors are classified afterwards,
+// so the path can't change between a check and the opening.
+func (fsys *Filesystem) openFile(p Path, flag int, mode os.FileMode, opts OpenOptions) (*os.File, error) {
+	if p.Empty() {
+		return nil, newError("open", p, ErrPathIsEmpty)
+	}
+
+	// without support for opening with noFollowFlag, the path is checked before
+	if opts.NoFollow && noFollowFlag == 0 {
+		if info, err := os.Lstat(p.String()); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return nil, newError("open", p, ErrIsSymlink)
+		}
+	}
+
+	if opts.NoFollow {
+		flag |= noFollowFlag
+	}
+
+	// a file is only known to be created when opened exclusively
+	exclusive := fsys.IgnoreUmask && flag&os.O_CREATE != 0 && flag&os.O_EXCL == 0
+
+	file, created, err := fsys.tryOpen(p, flag, mode, exclusive)
+	if err != nil && isNotFound(err) && flag&os.O_CREATE != 0 && !opts.NoParents {
+		if err := fsys.mkdirAll(p.Clean().Parent(), fsys.parentDirMode()); err != nil {
+			return nil, err
+		}
+		file, created, err = fsys.tryOpen(p, flag, mode, exclusive)
+	}
+
+	if err != nil {
+		return nil, openError(p, err, flag)
+	}
+
+	// directories can be opened for reading, but aren't files
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, wrapError(err)
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, newError("open", p, ErrPathIsDirectory)
+	}
+
+	// the mode is changed through the open file, so the file can't be
+	// replaced by another one in between
+	if created && fsys.IgnoreUmask {
+		if err := file.Chmod(mode); err != nil {
+			file.Close()
+			return nil, wrapError(err)
+		}
+	}
+
+	return file, nil
+}
+
+// tryOpen opens the file, first exclusively when requested, so it is known
+// whether the file was created.
+func (fsys *Filesystem) tryOpen(p Path, flag int, mode os.FileMode, exclusive bool) (*os.File, bool, error) {
+	if exclusive {
+		file, err := os.OpenFile(p.String(), flag|os.O_EXCL, mode)
+		if !errors.Is(err, os.ErrExist) {
+			return file, err == nil, err
+		}
+	}
+
+	file, err := os.OpenFile(p.String(), flag, mode)
+	return file, flag&os.O_EXCL != 0 && err == nil, err
+}
+
+// mkdirAll creates the directory with the given mode, along with the parents
+// that doesn't exist.
+func (fsys *Filesystem) mkdirAll(p Path, mode os.FileMode) error {
+	if info, err := os.Stat(p.String()); err == nil {
+		if info.IsDir() {
+			return nil
+		}
+		return newError("mkdir", p, syscall.ENOTDIR)
+	}
+
+	clean := p.Clean()
+	if parent := clean.Parent(); parent != clean {
+		if err := fsys.mkdirAll(parent, fsys.parentDirMode()); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Mkdir(p.String(), mode); err != nil {
+		// the directory may have been created in the meantime
+		if info, statErr := os.Lstat(p.String()); statErr == nil && info.IsDir() {
+			return nil
+		}
+		return wrapError(err)
+	}
+
+	// the umask only removes bits, so the directory is never more accessible
+	// than requested before this change
+	if fsys.IgnoreUmask {
+		return wrapError(os.Chmod(p.String(), mode))
+	}
+
+	return nil
+}
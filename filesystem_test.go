@@ -0,0 +1,114 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestFilesystem(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		tests := []struct {
+			fsys      *fs.Filesystem
+			fileMode  os.FileMode
+			dirMode   os.FileMode
+			parentDir os.FileMode
+		}{
+			{fsys: &fs.Filesystem{}, fileMode: 0644, dirMode: 0755, parentDir: 0755},
+			{fsys: &fs.Filesystem{FileMode: 0600, DirMode: 0700, ParentDirMode: 0700}, fileMode: 0600, dirMode: 0700, parentDir: 0700},
+			{fsys: &fs.Filesystem{DirMode: 0700}, fileMode: 0644, dirMode: 0700, parentDir: 0755},
+			{fsys: &fs.Filesystem{FileMode: 0666, DirMode: 0777, ParentDirMode: 0750, IgnoreUmask: true}, fileMode: 0666, dirMode: 0777, parentDir: 0750},
+		}
+
+		for i, test := range tests {
+			base := root.Join(string(rune('a' + i)))
+
+			if err := test.fsys.MkdirAll(base.Join("parent/dir")); err != nil {
+				t.Errorf("Case %d, error creating directory: %v", i, err)
+				continue
+			}
+
+			file, err := test.fsys.Create(base.Join("other/file.txt"))
+			if err != nil {
+				t.Errorf("Case %d, error creating file: %v", i, err)
+				continue
+			}
+			file.Close()
+
+			modes := map[string]os.FileMode{
+				"parent":         os.ModeDir | test.parentDir,
+				"parent/dir":     os.ModeDir | test.dirMode,
+				"other":          os.ModeDir | test.parentDir,
+				"other/file.txt": test.fileMode,
+			}
+
+			for path, expected := range modes {
+				if mode, err := base.Join(path).Mode(); err != nil || mode != expected {
+					t.Errorf("Case %d, error testing mode of '%s': expected '%v', received '%v' (%v)", i, path, expected, mode, err)
+				}
+			}
+		}
+
+		// the modes of existing paths are kept
+		fsys := &fs.Filesystem{FileMode: 0600, DirMode: 0700, IgnoreUmask: true}
+		if err := fsys.MkdirAll(root.Join("a/parent")); err != nil {
+			t.Errorf("Error creating existing directory: %v", err)
+		}
+
+		file, err := fsys.Append(root.Join("a/other/file.txt"))
+		if err != nil {
+			t.Errorf("Error opening existing file: %v", err)
+		} else {
+			file.Close()
+		}
+
+		if mode, _ := root.Join("a/parent").Mode(); mode != os.ModeDir|0755 {
+			t.Errorf("Error testing mode of existing directory: expected '%v', received '%v'", os.ModeDir|0755, mode)
+		}
+
+		if mode, _ := root.Join("a/other/file.txt").Mode(); mode != 0644 {
+			t.Errorf("Error testing mode of existing file: expected '%v', received '%v'", os.FileMode(0644), mode)
+		}
+
+		if err := fsys.MkdirAll(root.Join("a/other/file.txt/dir")); err == nil {
+			t.Errorf("Error testing directory inside a file: expected a error")
+		}
+
+		if _, err := fsys.Create(""); !errors.Is(err, fs.ErrPathIsEmpty) {
+			t.Errorf("Error testing empty path: expected '%v', received '%v'", fs.ErrPathIsEmpty, err)
+		}
+	})
+}
+
+func TestDefaultFilesystem(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		defer func(fsys *fs.Filesystem) { fs.DefaultFilesystem = fsys }(fs.DefaultFilesystem)
+		fs.DefaultFilesystem = &fs.Filesystem{FileMode: 0600, DirMode: 0700, ParentDirMode: 0700}
+
+		if err := root.Join("secrets/keys").MkdirAll(); err != nil {
+			t.Errorf("Error creating directory: %v", err)
+		}
+
+		if err := root.Join("secrets/token").Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+		}
+
+		modes := map[string]os.FileMode{
+			"secrets":       os.ModeDir | 0700,
+			"secrets/keys":  os.ModeDir | 0700,
+			"secrets/token": 0600,
+		}
+
+		for path, expected := range modes {
+			if mode, err := root.Join(path).Mode(); err != nil || mode != expected {
+				t.Errorf("Error testing mode of '%s': expected '%v', received '%v' (%v)", path, expected, mode, err)
+			}
+		}
+	})
+}
@@ -49,9 +49,9 @@ func Append(path string) (*os.File, error) {
 	return Path(path).Append()
 }
 
-// RemoveAll files or directory in the given path
-func RemoveAll(path string) {
-	Path(path).RemoveAll()
+// RemoveAll files or directory in the given path, refusing to remove protected paths
+func RemoveAll(path string) error {
+	return Path(path).RemoveAll()
 }
 
 // ReadAll returns all the content of a file
@@ -2,7 +2,7 @@ package fs_test
 
 import (
 	"bytes"
-	"os"
+	"errors"
 	"reflect"
 	"syscall"
 	"testing"
@@ -31,7 +31,7 @@ func TestMkdir(t *testing.T) {
 			},
 			{
 				source: "",
-				expected: &os.PathError{
+				expected: &fs.Error{
 					Op:   "mkdir",
 					Path: "",
 					Err:  syscall.ENOENT,
@@ -177,19 +177,24 @@ func TestOpen(t *testing.T) {
 			{path: root.Join("does/not/exists.txt"), expected: fs.ErrFileDoesNotExist},
 			{path: root.Join("does/not"), expected: fs.ErrFileDoesNotExist},
 			{path: root.Join("does/not/../exists.txt"), expected: nil},
+			{path: root.Join("does/exists.txt/child"), expected: fs.ErrFileDoesNotExist},
 			{path: fs.Path(""), expected: fs.ErrFileDoesNotExist},
 		}
 
 		for i, test := range tests {
 			path := test.path
 
-			if _, err := path.Open(); err != test.expected {
+			if _, err := path.Open(); !errors.Is(err, test.expected) {
 				t.Errorf("Case %d, error testing open: expected '%v', received '%v'", i, test.expected, err)
 			}
 
-			if _, err := fs.Open(path.String()); err != test.expected {
+			if _, err := fs.Open(path.String()); !errors.Is(err, test.expected) {
 				t.Errorf("Case %d, error testing open: expected '%v', received '%v'", i, test.expected, err)
 			}
+
+			if _, err := path.ReadAll(); !errors.Is(err, test.expected) {
+				t.Errorf("Case %d, error testing read all: expected '%v', received '%v'", i, test.expected, err)
+			}
 		}
 	})
 }
@@ -246,10 +251,10 @@ func TestReadAllError(t *testing.T) {
 	}
 
 	for i, test := range tests {
-		if _, err := test.source.ReadAll(); err != test.expected {
+		if _, err := test.source.ReadAll(); !errors.Is(err, test.expected) {
 			t.Errorf("Case %d, error testing ReadAll: %v", i, err)
 		}
-		if _, err := fs.ReadAll(test.source.String()); err != test.expected {
+		if _, err := fs.ReadAll(test.source.String()); !errors.Is(err, test.expected) {
 			t.Errorf("Case %d, error testing ReadAll: %v", i, err)
 		}
 	}
@@ -292,10 +297,10 @@ func TestReadDir(t *testing.T) {
 				t.Errorf("Case %d, error writing to file: %v", i, err)
 			}
 
-			if _, err := test.source.Parent().ReadDir(); err != test.expectedParent {
+			if _, err := test.source.Parent().ReadDir(); !errors.Is(err, test.expectedParent) {
 				t.Errorf("Case %d, error testing read dir: expected '%v', received '%v'", i, test.expected, err)
 			}
-			if _, err := test.source.ReadDir(); err != test.expected {
+			if _, err := test.source.ReadDir(); !errors.Is(err, test.expected) {
 				t.Errorf("Case %d, error testing read dir: expected '%v', received '%v'", i, test.expected, err)
 			}
 		}
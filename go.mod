@@ -1,5 +1,8 @@
 module github.com/plateausnetwork/fs
 
-go 1.13
+go 1.17
 
-require github.com/google/uuid v1.1.1
+require (
+	github.com/google/uuid v1.1.1
+	golang.org/x/sys v0.7.0
+)
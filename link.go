@@ -0,0 +1,17 @@
+package fs
+
+import (
+	"os"
+)
+
+// Link creates dest as a hard link to the file in the path
+func (p Path) Link(dest Path) error {
+	return wrapError(os.Link(p.String(), dest.String()))
+}
+
+// SameFile returns true when both paths refer to the same file, i.e. they
+// have the same device and inode. Symbolic links are followed.
+func (p Path) SameFile(other Path) bool {
+	a, b := p.Info(), other.Info()
+	return a != nil && b != nil && os.SameFile(a, b)
+}
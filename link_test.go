@@ -0,0 +1,114 @@
+package fs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestLink(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		file := root.Join("file.txt")
+		other := root.Join("other.txt")
+
+		for _, path := range []fs.Path{file, other} {
+			f, err := path.Create()
+			if err != nil {
+				t.Errorf("Error creating file: %v", err)
+				return
+			}
+			f.Close()
+		}
+
+		if err := file.Link(root.Join("link.txt")); err != nil {
+			t.Errorf("Error creating link: %v", err)
+		}
+
+		if err := root.Join("symlink.txt").Symlink(file); err != nil {
+			t.Errorf("Error creating symbolic link: %v", err)
+		}
+
+		if err := file.Link(other); !errors.Is(err, fs.ErrPathExists) {
+			t.Errorf("Error testing link to existing path: expected '%v', received '%v'", fs.ErrPathExists, err)
+		}
+
+		tests := []struct {
+			a, b     fs.Path
+			expected bool
+		}{
+			{a: file, b: file, expected: true},
+			{a: file, b: root.Join("link.txt"), expected: true},
+			{a: root.Join("symlink.txt"), b: root.Join("link.txt"), expected: true},
+			{a: file, b: root.Join("./file.txt"), expected: true},
+			{a: file, b: other, expected: false},
+			{a: file, b: root.Join("missing"), expected: false},
+		}
+
+		for i, test := range tests {
+			if received := test.a.SameFile(test.b); received != test.expected {
+				t.Errorf("Case %d, error testing SameFile: expected '%v', received '%v'", i, test.expected, received)
+			}
+		}
+	})
+}
+
+func TestCopyToWithOptionsHardLinks(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		src := root.Join("src")
+
+		if err := createWalkTree(src); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		for _, link := range []fs.Path{src.Join("a2.txt"), src.Join("b/d/a3.txt")} {
+			if err := src.Join("a.txt").Link(link); err != nil {
+				t.Errorf("Error creating link: %v", err)
+				return
+			}
+		}
+
+		tests := []struct {
+			preserve bool
+			expected bool
+		}{
+			{preserve: false, expected: false},
+			{preserve: true, expected: true},
+		}
+
+		for i, test := range tests {
+			dest := root.Join("dest").Join(string(rune('a' + i)))
+
+			err := src.CopyToWithOptions(dest, fs.CopyOptions{PreserveHardLinks: test.preserve})
+			if errors.Is(err, fs.ErrNotSupported) {
+				t.Skip("hard links are not supported")
+			}
+
+			if err != nil {
+				t.Errorf("Case %d, error copying: %v", i, err)
+				continue
+			}
+
+			for _, link := range []fs.Path{dest.Join("a2.txt"), dest.Join("b/d/a3.txt")} {
+				if received := dest.Join("a.txt").SameFile(link); received != test.expected {
+					t.Errorf("Case %d, error testing hard link '%s': expected '%v', received '%v'", i, link, test.expected, received)
+				}
+
+				if data, _ := link.ReadAll(); string(data) != "a" {
+					t.Errorf("Case %d, unexpected content of '%s': '%s'", i, link, data)
+				}
+			}
+
+			if dest.Join("a.txt").SameFile(src.Join("a.txt")) {
+				t.Errorf("Case %d, the destination should not be linked to the source", i)
+			}
+
+			if dest.Join("g.log").SameFile(dest.Join("a.txt")) {
+				t.Errorf("Case %d, files not linked in the source should not be linked", i)
+			}
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// OpenOptions configures the opening done by OpenFile
+type OpenOptions struct {
+	// Mode is the mode of the file when it is created. Zero means the file
+	// mode of the Filesystem.
+	Mode os.FileMode
+
+	// NoParents disables the creation of the missing parents of the file
+	NoParents bool
+
+	// NoFollow makes the opening fail with ErrIsSymlink when the path is a
+	// symbolic link, instead of opening the file it points to.
+	NoFollow bool
+}
+
+// openError converts a error of os.OpenFile, opened with the given flags, to
+// one of the errors of the package.
+func openError(p Path, err error, flag int) error {
+	switch {
+	case flag&noFollowFlag != 0 && isSymlinkError(err):
+		return newError("open", p, ErrIsSymlink)
+	case errors.Is(err, syscall.EISDIR):
+		return newError("open", p, ErrPathIsDirectory)
+	case errors.Is(err, os.ErrExist) && p.DirExists():
+		return newError("open", p, ErrPathIsDirectory)
+	case isNotFound(err) && flag&os.O_CREATE == 0:
+		return newError("open", p, ErrFileDoesNotExist)
+	}
+	return wrapError(err)
+}
+
+// openRegular opens the regular file for reading, failing with
+// ErrFileDoesNotExist when the path is missing or isn't a regular file. The
+// file is opened without blocking, so named pipes can be opened to be refused.
+func openRegular(op string, p Path) (*os.File, os.FileInfo, error) {
+	if p.Empty() {
+		return nil, nil, newError(op, p, ErrFileDoesNotExist)
+	}
+
+	file, err := os.OpenFile(p.String(), openFileFlag|nonBlockFlag, 0)
+	if err != nil {
+		// a path under a file doesn't exist either
+		if isNotFound(err) || errors.Is(err, syscall.ENOTDIR) {
+			return nil, nil, newError(op, p, ErrFileDoesNotExist)
+		}
+		return nil, nil, wrapError(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, wrapError(err)
+	}
+
+	if !info.Mode().IsRegular() {
+		file.Close()
+		return nil, nil, newError(op, p, ErrFileDoesNotExist)
+	}
+
+	return file, info, nil
+}
@@ -0,0 +1,20 @@
+package fs
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	// noFollowFlag makes the opening fail when the path is a symbolic link
+	noFollowFlag = syscall.O_NOFOLLOW
+
+	// nonBlockFlag makes the opening of named pipes not wait for a writer
+	nonBlockFlag = syscall.O_NONBLOCK
+)
+
+// isSymlinkError returns true when the error is the one of opening a symbolic
+// link with noFollowFlag.
+func isSymlinkError(err error) bool {
+	return errors.Is(err, syscall.ELOOP)
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+// +build !linux
+
+package fs
+
+const (
+	// noFollowFlag is not available on this platform, where the path is
+	// checked before being opened.
+	noFollowFlag = 0
+
+	// nonBlockFlag is not used on this platform
+	nonBlockFlag = 0
+)
+
+// isSymlinkError returns true when the error is the one of opening a symbolic
+// link with noFollowFlag, which is never the case on this platform.
+func isSymlinkError(err error) bool {
+	return false
+}
@@ -0,0 +1,322 @@
+package fs_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestCreateNew(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		path := root.Join("a/b/file.txt")
+
+		file, err := path.CreateNew()
+		if err != nil {
+			t.Errorf("Error creating new file: %v", err)
+			return
+		}
+		_, _ = file.Write([]byte("content"))
+		file.Close()
+
+		tests := []struct {
+			path     fs.Path
+			expected error
+		}{
+			{path: path, expected: fs.ErrPathExists},
+			{path: root.Join("a"), expected: fs.ErrPathIsDirectory},
+			{path: "", expected: fs.ErrPathIsEmpty},
+		}
+
+		for i, test := range tests {
+			if _, err := test.path.CreateNew(); !errors.Is(err, test.expected) {
+				t.Errorf("Case %d, error testing create new: expected '%v', received '%v'", i, test.expected, err)
+			}
+		}
+
+		if data, _ := path.ReadAll(); string(data) != "content" {
+			t.Errorf("Existing file should not be changed, received '%s'", data)
+		}
+	})
+}
+
+func TestOpenReadWrite(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		path := root.Join("file.txt")
+
+		if _, err := path.OpenReadWrite(); !errors.Is(err, fs.ErrFileDoesNotExist) {
+			t.Errorf("Error testing open of missing file: expected '%v', received '%v'", fs.ErrFileDoesNotExist, err)
+		}
+
+		if err := ioutil.WriteFile(path.String(), []byte("0123456789"), 0644); err != nil {
+			t.Errorf("Error writing file: %v", err)
+			return
+		}
+
+		file, err := path.OpenReadWrite()
+		if err != nil {
+			t.Errorf("Error opening file: %v", err)
+			return
+		}
+
+		buffer := make([]byte, 5)
+		if _, err := file.Read(buffer); err != nil || string(buffer) != "01234" {
+			t.Errorf("Error testing read: expected '01234', received '%s' (%v)", buffer, err)
+		}
+
+		if _, err := file.Write([]byte("abc")); err != nil {
+			t.Errorf("Error testing write: %v", err)
+		}
+		file.Close()
+
+		if data, _ := path.ReadAll(); string(data) != "01234abc89" {
+			t.Errorf("Error testing open read write: expected '01234abc89', received '%s'", data)
+		}
+	})
+}
+
+func TestOpenNoFollow(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		path := root.Join("file.txt")
+
+		if err := path.Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+
+		links := map[string]fs.Path{"link": path, "dangling": root.Join("missing")}
+		for link, target := range links {
+			if err := root.Join(link).Symlink(target); err != nil {
+				t.Errorf("Error creating link: %v", err)
+				return
+			}
+		}
+
+		if file, err := path.OpenNoFollow(); err != nil {
+			t.Errorf("Error opening file: %v", err)
+		} else {
+			file.Close()
+		}
+
+		tests := []struct {
+			path     fs.Path
+			expected error
+		}{
+			{path: root.Join("link"), expected: fs.ErrIsSymlink},
+			{path: root.Join("dangling"), expected: fs.ErrIsSymlink},
+			{path: root.Join("missing"), expected: fs.ErrFileDoesNotExist},
+			{path: root, expected: fs.ErrPathIsDirectory},
+		}
+
+		for i, test := range tests {
+			if _, err := test.path.OpenNoFollow(); !errors.Is(err, test.expected) {
+				t.Errorf("Case %d, error testing open no follow: expected '%v', received '%v'", i, test.expected, err)
+			}
+		}
+
+		// links to directories are refused too
+		if err := root.Join("dirlink").Symlink(root); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		if _, err := root.Join("dirlink").OpenNoFollow(); !errors.Is(err, fs.ErrIsSymlink) {
+			t.Errorf("Error testing open no follow of link to directory: expected '%v', received '%v'", fs.ErrIsSymlink, err)
+		}
+	})
+}
+
+func TestOpenFile(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		flag := os.O_WRONLY | os.O_CREATE
+		tests := []struct {
+			path     fs.Path
+			opts     fs.OpenOptions
+			mode     os.FileMode
+			expected error
+		}{
+			{path: root.Join("a/file.txt"), opts: fs.OpenOptions{}, mode: 0644},
+			{path: root.Join("b/file.txt"), opts: fs.OpenOptions{NoParents: true}, expected: fs.ErrNotFound},
+			{path: root.Join("a/other.txt"), opts: fs.OpenOptions{NoParents: true, Mode: 0600}, mode: 0600},
+			{path: root.Join("a/link"), opts: fs.OpenOptions{NoFollow: true}, expected: fs.ErrIsSymlink},
+		}
+
+		if err := root.Join("a").MkdirAll(); err != nil {
+			t.Errorf("Error creating directory: %v", err)
+			return
+		}
+
+		if err := root.Join("a/link").Symlink("file.txt"); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		for i, test := range tests {
+			file, err := test.path.OpenFile(flag, test.opts)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Case %d, error testing open file: expected '%v', received '%v'", i, test.expected, err)
+				continue
+			}
+
+			if err != nil {
+				continue
+			}
+			file.Close()
+
+			if mode, err := test.path.Mode(); err != nil || mode != test.mode {
+				t.Errorf("Case %d, error testing mode: expected '%v', received '%v' (%v)", i, test.mode, mode, err)
+			}
+		}
+
+		if root.Join("b").Exists() {
+			t.Errorf("Parents should not be created when disabled")
+		}
+	})
+}
+
+func TestOpenConcurrentSwap(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		path := root.Join("target")
+		other := root.Join("other")
+
+		if err := ioutil.WriteFile(path.String(), []byte("content"), 0644); err != nil {
+			t.Errorf("Error writing file: %v", err)
+			return
+		}
+
+		if err := other.MkdirAll(); err != nil {
+			t.Errorf("Error creating directory: %v", err)
+			return
+		}
+
+		if err := exchange(path, other); err != nil {
+			t.Skipf("atomic exchange is not supported: %v", err)
+		}
+
+		// the path keeps changing between a directory and a file, always existing
+		done := make(chan struct{})
+		var swapper sync.WaitGroup
+		swapper.Add(1)
+
+		go func() {
+			defer swapper.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					_ = exchange(path, other)
+				}
+			}
+		}()
+
+		var readers sync.WaitGroup
+		for r := 0; r < 4; r++ {
+			readers.Add(1)
+			go func(r int) {
+				defer readers.Done()
+
+				for i := 0; i < 5000; i++ {
+					if file, err := path.Open(); err == nil {
+						info, statErr := file.Stat()
+						file.Close()
+
+						if statErr != nil || !info.Mode().IsRegular() {
+							t.Errorf("Case %d/%d, open returned something other than a file: %v (%v)", r, i, info.Mode(), statErr)
+							return
+						}
+					} else if !errors.Is(err, fs.ErrFileDoesNotExist) {
+						t.Errorf("Case %d/%d, error testing open: expected '%v', received '%v'", r, i, fs.ErrFileDoesNotExist, err)
+						return
+					}
+
+					if data, err := path.ReadAll(); err == nil && string(data) != "content" {
+						t.Errorf("Case %d/%d, error testing read all: expected 'content', received '%s'", r, i, data)
+						return
+					} else if err != nil && !errors.Is(err, fs.ErrFileDoesNotExist) {
+						t.Errorf("Case %d/%d, error testing read all: expected '%v', received '%v'", r, i, fs.ErrFileDoesNotExist, err)
+						return
+					}
+
+					if file, err := path.OpenReadWrite(); err == nil {
+						info, statErr := file.Stat()
+						file.Close()
+
+						if statErr != nil || !info.Mode().IsRegular() {
+							t.Errorf("Case %d/%d, open read write returned something other than a file: %v (%v)", r, i, info.Mode(), statErr)
+							return
+						}
+					} else if !errors.Is(err, fs.ErrPathIsDirectory) {
+						t.Errorf("Case %d/%d, error testing open read write: expected '%v', received '%v'", r, i, fs.ErrPathIsDirectory, err)
+						return
+					}
+				}
+			}(r)
+		}
+
+		readers.Wait()
+		close(done)
+		swapper.Wait()
+	})
+}
+
+func TestCreateConcurrentParents(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		const workers = 32
+		var wg sync.WaitGroup
+
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				// every file shares the missing parents with the others
+				path := root.Join("a/b/c").Join(string(rune('a' + i%8))).Join(string(rune('a'+i)) + ".txt")
+
+				file, err := path.Create()
+				if err != nil {
+					t.Errorf("Case %d, error creating file with missing parents: %v", i, err)
+					return
+				}
+				file.Close()
+			}(i)
+		}
+
+		wg.Wait()
+
+		if count := root.Count(fs.WalkFiles); count != workers {
+			t.Errorf("Error testing concurrent create: expected %d files, received %d", workers, count)
+		}
+	})
+}
+
+func TestOpenNamedPipe(t *testing.T) {
+	WithTempDir(func(dir string) {
+		path := fs.Path(dir).Join("pipe")
+
+		if err := mkfifo(path); err != nil {
+			t.Skipf("named pipes are not supported: %v", err)
+		}
+
+		// a pipe without writers must be refused without blocking
+		if _, err := path.Open(); !errors.Is(err, fs.ErrFileDoesNotExist) {
+			t.Errorf("Error testing open of named pipe: expected '%v', received '%v'", fs.ErrFileDoesNotExist, err)
+		}
+
+		if _, err := path.ReadAll(); !errors.Is(err, fs.ErrFileDoesNotExist) {
+			t.Errorf("Error testing read all of named pipe: expected '%v', received '%v'", fs.ErrFileDoesNotExist, err)
+		}
+	})
+}
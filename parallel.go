@@ -0,0 +1,355 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// ParallelWalkOptions configures the walking routine done by ParallelWalk
+type ParallelWalkOptions struct {
+	WalkOptions
+
+	// Workers is the number of directories read at the same time.
+	// Zero means the number of CPUs available.
+	Workers int
+
+	// Ordered makes the walker function be called sequentially and in the same
+	// order as WalkDir would call it, while the directories are still read ahead
+	// by the workers. Otherwise, the walker function is called concurrently by the
+	// workers and must be safe for concurrent use.
+	Ordered bool
+}
+
+// queuedDirsPerWorker is the number of directories waiting to be read, for
+// each worker, beyond which the workers read the directories they find right
+// away, depth-first.
+const queuedDirsPerWorker = 64
+
+// ParallelWalk works like WalkDir, but spreads the reading of the directories
+// across multiple workers. In unordered mode, the directories waiting to be
+// read are queued up to a limit and, with SortNone, directories are read in
+// pages, so the memory used does not depend on the number of entries of the
+// tree. Sorting requires whole directories to be read, and the ordered mode
+// keeps the entries of the directories being visited and read ahead.
+// The first error returned by the walker function cancels the remaining work
+// and is returned, except for ErrStopWalk, which just stops the walking routine.
+func (p Path) ParallelWalk(opts ParallelWalkOptions, walker func(entry *DirEntry) error) error {
+	info, err := statDir("walk", p)
+	if err != nil {
+		return err
+	}
+
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+
+	if opts.Ordered {
+		err = newOrderedWalk(opts, walker).walk(p, info)
+	} else {
+		err = newUnorderedWalk(opts, walker).walk(p, info)
+	}
+
+	if err != nil && err != ErrStopWalk {
+		return err
+	}
+
+	return nil
+}
+
+// dirJob is a directory waiting to be read by the unordered walking routine
+type dirJob struct {
+	path  Path
+	depth int
+
+	// ancestors of the directory, including itself, used to detect loops
+	ancestors []os.FileInfo
+}
+
+// unorderedWalk holds the state of a ParallelWalk call in unordered mode
+type unorderedWalk struct {
+	opts   ParallelWalkOptions
+	walker func(entry *DirEntry) error
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []dirJob
+	limit  int
+	active int
+	err    error
+}
+
+func newUnorderedWalk(opts ParallelWalkOptions, walker func(entry *DirEntry) error) *unorderedWalk {
+	w := &unorderedWalk{opts: opts, walker: walker, limit: opts.Workers * queuedDirsPerWorker}
+	w.cond = sync.NewCond(&w.mu)
+	return w
+}
+
+// walk runs the workers until every directory is read or an error happens
+func (w *unorderedWalk) walk(root Path, info os.FileInfo) error {
+	w.queue = append(w.queue, dirJob{path: root, depth: 1, ancestors: []os.FileInfo{info}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work()
+		}()
+	}
+	wg.Wait()
+
+	return w.err
+}
+
+// work takes directories from the queue until there is nothing left to do
+func (w *unorderedWalk) work() {
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && w.active > 0 && w.err == nil {
+			w.cond.Wait()
+		}
+
+		if len(w.queue) == 0 || w.err != nil {
+			w.mu.Unlock()
+			w.cond.Broadcast()
+			return
+		}
+
+		// taking the most recent directory keeps the queue small, like a
+		// depth-first walking routine
+		job := w.queue[len(w.queue)-1]
+		w.queue = w.queue[:len(w.queue)-1]
+		w.active++
+		w.mu.Unlock()
+
+		err := w.read(job)
+
+		w.mu.Lock()
+		w.active--
+		if err != nil && w.err == nil {
+			w.err = err
+		}
+		w.mu.Unlock()
+		w.cond.Broadcast()
+	}
+}
+
+// read visits the entries of a directory and queues its subdirectories
+func (w *unorderedWalk) read(job dirJob) error {
+	if w.opts.MaxDepth > 0 && job.depth > w.opts.MaxDepth {
+		return nil
+	}
+
+	err := readDirPages(job.path, w.opts.Sort, func(entries []os.DirEntry) error {
+		for _, entry := range entries {
+			if w.canceled() {
+				return filepath.SkipDir
+			}
+
+			e := newDirEntry(job.path, entry, job.depth)
+
+			if !w.opts.resolve(e, job.ancestors) {
+				continue
+			}
+
+			if ok, err := w.opts.selects(e); err != nil {
+				return err
+			} else if ok {
+				if err := w.walker(e); err != nil {
+					if err == filepath.SkipDir && e.IsDir() {
+						continue
+					}
+					return err
+				}
+			}
+
+			if e.IsDir() {
+				next := dirJob{path: e.Path(), depth: job.depth + 1}
+
+				if w.opts.FollowSymlinks {
+					info, err := e.Info()
+					if err != nil {
+						return err
+					}
+					next.ancestors = append(append([]os.FileInfo{}, job.ancestors...), info)
+				}
+
+				if err := w.push(next); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	// skipping the remaining entries stops the reading of the directory
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+// push queues a directory to be read by any of the workers or, when the queue
+// is full, reads it right away.
+func (w *unorderedWalk) push(job dirJob) error {
+	w.mu.Lock()
+	if len(w.queue) < w.limit {
+		w.queue = append(w.queue, job)
+		w.mu.Unlock()
+		w.cond.Signal()
+		return nil
+	}
+	w.mu.Unlock()
+
+	return w.read(job)
+}
+
+// canceled returns true when the walking routine must stop
+func (w *unorderedWalk) canceled() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err != nil
+}
+
+// dirListing is the result of reading a directory ahead of time
+type dirListing struct {
+	entries []os.DirEntry
+	err     error
+	done    chan struct{}
+}
+
+// orderedWalk holds the state of a ParallelWalk call in ordered mode
+type orderedWalk struct {
+	walkState
+
+	// slots limits the number of listings read ahead and not yet consumed
+	slots   chan struct{}
+	pending map[Path]*dirListing
+}
+
+func newOrderedWalk(opts ParallelWalkOptions, walker func(entry *DirEntry) error) *orderedWalk {
+	return &orderedWalk{
+		walkState: walkState{opts: opts.WalkOptions, walker: walker},
+		slots:     make(chan struct{}, opts.Workers),
+		pending:   make(map[Path]*dirListing),
+	}
+}
+
+// walk visits the tree sequentially, like walkState, reading directories ahead
+func (w *orderedWalk) walk(root Path, info os.FileInfo) error {
+	w.ancestors = []os.FileInfo{info}
+	return w.walkDir(root, 1)
+}
+
+// walkDir visits the entries of dir, which are all at the given depth
+func (w *orderedWalk) walkDir(dir Path, depth int) error {
+	if w.opts.MaxDepth > 0 && depth > w.opts.MaxDepth {
+		return nil
+	}
+
+	entries, err := w.listing(dir)
+	if err != nil {
+		return err
+	}
+
+	// read the subdirectories ahead, as long as there are workers available
+	if w.opts.MaxDepth <= 0 || depth < w.opts.MaxDepth {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				w.prefetch(dir.Join(entry.Name()))
+			}
+		}
+	}
+
+	for i, entry := range entries {
+		e := newDirEntry(dir, entry, depth)
+
+		if !w.opts.resolve(e, w.ancestors) {
+			continue
+		}
+
+		if err := w.visit(e); err != nil {
+			if err == filepath.SkipDir {
+				w.discard(e.Path())
+				if e.IsDir() {
+					continue
+				}
+				for _, skipped := range entries[i+1:] {
+					w.discard(dir.Join(skipped.Name()))
+				}
+				return nil
+			}
+			return err
+		}
+
+		if e.IsDir() {
+			if err := w.descend(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// descend walks into the directory of the given entry
+func (w *orderedWalk) descend(e *DirEntry) error {
+	if w.opts.FollowSymlinks {
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		w.ancestors = append(w.ancestors, info)
+		defer func() { w.ancestors = w.ancestors[:len(w.ancestors)-1] }()
+	}
+
+	return w.walkDir(e.Path(), e.Depth()+1)
+}
+
+// prefetch starts reading a directory when there is a worker available
+func (w *orderedWalk) prefetch(dir Path) {
+	select {
+	case w.slots <- struct{}{}:
+	default:
+		return
+	}
+
+	l := &dirListing{done: make(chan struct{})}
+	w.pending[dir] = l
+
+	go func() {
+		l.entries, l.err = readDirEntries(dir, w.opts.Sort)
+		close(l.done)
+	}()
+}
+
+// listing returns the entries of a directory, waiting for it to be read
+// when it was read ahead, or reading it right away otherwise
+func (w *orderedWalk) listing(dir Path) ([]os.DirEntry, error) {
+	l, ok := w.pending[dir]
+	if !ok {
+		return readDirEntries(dir, w.opts.Sort)
+	}
+
+	<-l.done
+	delete(w.pending, dir)
+	<-w.slots
+	return l.entries, l.err
+}
+
+// discard drops a directory read ahead that is not going to be visited,
+// releasing its worker slot as soon as the reading is done
+func (w *orderedWalk) discard(dir Path) {
+	l, ok := w.pending[dir]
+	if !ok {
+		return
+	}
+
+	delete(w.pending, dir)
+	go func() {
+		<-l.done
+		<-w.slots
+	}()
+}
@@ -0,0 +1,198 @@
+package fs_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestParallelWalk(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createBenchmarkTree(root, 4, 3); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		tests := []fs.WalkOptions{
+			{Sort: fs.SortByName},
+			{Type: fs.WalkFiles, Sort: fs.SortByName},
+			{Type: fs.WalkDirs, Sort: fs.SortByName, MaxDepth: 2},
+			{Sort: fs.SortByName, MinDepth: 3, Filters: []fs.WalkFilter{fs.NameFilter("file[12]")}},
+		}
+
+		for i, opts := range tests {
+			var expected []fs.Path
+			if err := root.WalkDir(opts, func(entry *fs.DirEntry) error {
+				expected = append(expected, entry.Path())
+				return nil
+			}); err != nil {
+				t.Errorf("Case %d, error walking: %v", i, err)
+				continue
+			}
+
+			for _, workers := range []int{1, 4} {
+				var ordered []fs.Path
+				if err := root.ParallelWalk(fs.ParallelWalkOptions{WalkOptions: opts, Workers: workers, Ordered: true}, func(entry *fs.DirEntry) error {
+					ordered = append(ordered, entry.Path())
+					return nil
+				}); err != nil {
+					t.Errorf("Case %d, error walking in order: %v", i, err)
+				}
+
+				if !reflect.DeepEqual(ordered, expected) {
+					t.Errorf("Case %d, error testing ordered walk with %d workers: expected '%v', received '%v'", i, workers, expected, ordered)
+				}
+
+				var mu sync.Mutex
+				var unordered []fs.Path
+				if err := root.ParallelWalk(fs.ParallelWalkOptions{WalkOptions: opts, Workers: workers}, func(entry *fs.DirEntry) error {
+					mu.Lock()
+					defer mu.Unlock()
+					unordered = append(unordered, entry.Path())
+					return nil
+				}); err != nil {
+					t.Errorf("Case %d, error walking out of order: %v", i, err)
+				}
+
+				sort.Slice(unordered, func(i, j int) bool { return unordered[i] < unordered[j] })
+				sorted := append([]fs.Path{}, expected...)
+				sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+				if !reflect.DeepEqual(unordered, sorted) {
+					t.Errorf("Case %d, error testing unordered walk with %d workers: expected '%v', received '%v'", i, workers, sorted, unordered)
+				}
+			}
+		}
+	})
+}
+
+func TestParallelWalkErrors(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createBenchmarkTree(root, 4, 3); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		tests := []struct {
+			returned error
+			expected error
+		}{
+			{returned: fs.ErrNotFound, expected: fs.ErrNotFound},
+			{returned: fs.ErrStopWalk, expected: nil},
+		}
+
+		for i, test := range tests {
+			for _, ordered := range []bool{true, false} {
+				var visited int64
+				opts := fs.ParallelWalkOptions{WalkOptions: fs.WalkOptions{Type: fs.WalkFiles}, Workers: 4, Ordered: ordered}
+
+				err := root.ParallelWalk(opts, func(entry *fs.DirEntry) error {
+					if atomic.AddInt64(&visited, 1) == 10 {
+						return test.returned
+					}
+					return nil
+				})
+
+				if !errors.Is(err, test.expected) {
+					t.Errorf("Case %d, error testing parallel walk errors: expected '%v', received '%v'", i, test.expected, err)
+				}
+
+				// each worker stops after the entry being visited when the walking is canceled
+				if visited < 10 || visited > 10+int64(opts.Workers) {
+					t.Errorf("Case %d, error testing parallel walk cancelation: %d entries visited", i, visited)
+				}
+			}
+		}
+
+		if err := root.Join("file0").ParallelWalk(fs.ParallelWalkOptions{}, nil); !errors.Is(err, fs.ErrDirDoesNotExist) {
+			t.Errorf("Error testing parallel walk on a file: expected '%v', received '%v'", fs.ErrDirDoesNotExist, err)
+		}
+	})
+}
+
+func TestDiskUsage(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createWalkTree(root); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		tests := []struct {
+			path     fs.Path
+			expected int64
+			err      error
+		}{
+			{path: root, expected: 12},
+			{path: root.Join("b"), expected: 9},
+			{path: root.Join("g.log"), expected: 2},
+			{path: root.Join("none"), expected: 0, err: fs.ErrNotFound},
+		}
+
+		for i, test := range tests {
+			usage, err := test.path.DiskUsage()
+			if !errors.Is(err, test.err) {
+				t.Errorf("Case %d, error testing disk usage: expected '%v', received '%v'", i, test.err, err)
+			}
+
+			if usage != test.expected {
+				t.Errorf("Case %d, error testing disk usage: expected %d, received %d", i, test.expected, usage)
+			}
+		}
+	})
+}
+
+func BenchmarkParallelWalk(b *testing.B) {
+	benchmarkWithTree(b, func(root fs.Path) {
+		_ = root.ParallelWalk(fs.ParallelWalkOptions{}, func(entry *fs.DirEntry) error {
+			return nil
+		})
+	})
+}
+
+func BenchmarkParallelWalkOrdered(b *testing.B) {
+	benchmarkWithTree(b, func(root fs.Path) {
+		_ = root.ParallelWalk(fs.ParallelWalkOptions{Ordered: true}, func(entry *fs.DirEntry) error {
+			return nil
+		})
+	})
+}
+
+func TestParallelWalkWideTree(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		// many more directories than can be queued by the workers, and
+		// directories with more entries than a page
+		const width = 600
+		for i := 0; i < width; i++ {
+			if err := root.Join(fmt.Sprintf("dir%03d/sub", i)).MkdirAll(); err != nil {
+				t.Errorf("Error creating directory: %v", err)
+				return
+			}
+		}
+
+		for _, opts := range []fs.WalkOptions{{}, {Sort: fs.SortByName}, {FollowSymlinks: true}} {
+			var count int64
+			err := root.ParallelWalk(fs.ParallelWalkOptions{WalkOptions: opts, Workers: 2}, func(entry *fs.DirEntry) error {
+				atomic.AddInt64(&count, 1)
+				return nil
+			})
+
+			if err != nil || count != 2*width {
+				t.Errorf("Error testing wide tree: expected %d entries, received %d (%v)", 2*width, count, err)
+			}
+		}
+	})
+}
@@ -1,11 +1,13 @@
 package fs
 
 import (
-	"io"
-	"io/ioutil"
+	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"syscall"
 )
 
 // Path represents a valid filesystem path.
@@ -32,6 +34,8 @@ const (
 	openFileFlag   int = os.O_RDONLY
 	createFileFlag int = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
 	appendFileFlag int = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	newFileFlag    int = os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	updateFileFlag int = os.O_RDWR
 )
 
 // Info returns a info of a path
@@ -65,68 +69,120 @@ func (p Path) DirExists() bool {
 	return false
 }
 
-// Open opens the file specified by path for reading.
-func (p Path) Open() (*os.File, error) {
-	if !p.FileExists() {
-		return nil, ErrFileDoesNotExist
+// stat returns the information about the path. When the path does not exist,
+// the error returned wraps notFound, otherwise it is the error of the operating system.
+func stat(op string, p Path, notFound error) (os.FileInfo, error) {
+	info, err := os.Stat(p.String())
+	if err == nil {
+		return info, nil
+	}
+	if isNotFound(err) || errors.Is(err, syscall.ENOTDIR) {
+		return nil, newError(op, p, notFound)
+	}
+	return nil, wrapError(err)
+}
+
+// statDir works like stat, but also fails with ErrNotDirectory when the path
+// is not a directory.
+func statDir(op string, p Path) (os.FileInfo, error) {
+	info, err := stat(op, p, ErrDirDoesNotExist)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, newError(op, p, ErrNotDirectory)
 	}
+	return info, nil
+}
 
-	return open(p, openFileFlag, defaultFileMode)
+// Open opens the file specified by path for reading.
+func (p Path) Open() (*os.File, error) {
+	file, _, err := openRegular("open", p)
+	return file, err
 }
 
 // Create open the specified file for writing, creating a new file if necessary.
-// If the file already exists, it is overridden.
+// If the file already exists, it is overridden. The modes used are the ones
+// of DefaultFilesystem.
 func (p Path) Create() (*os.File, error) {
-	return open(p, createFileFlag, defaultFileMode)
+	return DefaultFilesystem.Create(p)
 }
 
 // Append works like create, but instead of discarding the content of an existing file,
 // it just appends the new data at the end of the file.
 func (p Path) Append() (*os.File, error) {
-	file, err := open(p, appendFileFlag, defaultFileMode)
-	if err != nil {
-		return nil, err
-	}
+	return DefaultFilesystem.Append(p)
+}
+
+// CreateNew creates a new file for writing, failing with ErrPathExists when
+// the path already exists.
+func (p Path) CreateNew() (*os.File, error) {
+	return DefaultFilesystem.OpenFile(p, newFileFlag, OpenOptions{})
+}
 
-	return file, nil
+// OpenReadWrite opens an existing file for reading and writing, without
+// discarding its content.
+func (p Path) OpenReadWrite() (*os.File, error) {
+	return DefaultFilesystem.OpenFile(p, updateFileFlag, OpenOptions{})
 }
 
-// RemoveAll files or directory in the given path
-func (p Path) RemoveAll() {
-	os.RemoveAll(p.String())
+// OpenNoFollow opens an existing file for reading, failing with ErrIsSymlink
+// when the path is a symbolic link.
+func (p Path) OpenNoFollow() (*os.File, error) {
+	return DefaultFilesystem.OpenFile(p, openFileFlag, OpenOptions{NoFollow: true})
 }
 
-// MkdirAll creates all directories that doesn't exists
+// OpenFile opens the file with the given flags, like os.OpenFile, with the
+// behavior configured by the options and the modes of DefaultFilesystem.
+func (p Path) OpenFile(flag int, opts OpenOptions) (*os.File, error) {
+	return DefaultFilesystem.OpenFile(p, flag, opts)
+}
+
+// CreateUnique creates a new file for writing at the path or, when it is taken,
+// at the first free path with a numbered suffix, as described by
+// Filesystem.CreateUnique. It returns the file and the path chosen.
+func (p Path) CreateUnique(pattern string) (*os.File, Path, error) {
+	return DefaultFilesystem.CreateUnique(p, pattern)
+}
+
+// MkdirAll creates all directories that doesn't exists, with the modes of
+// DefaultFilesystem.
 func (p Path) MkdirAll() error {
-	if err := os.MkdirAll(p.String(), defaultDirMode); err != nil {
-		return err
-	}
-	return nil
+	return DefaultFilesystem.MkdirAll(p)
 }
 
 // ReadAll returns all the content of a file
 func (p Path) ReadAll() ([]byte, error) {
-	if !p.FileExists() {
-		return nil, ErrFileDoesNotExist
+	file, info, err := openRegular("read", p)
+	if err != nil {
+		return nil, err
 	}
-	return ioutil.ReadFile(p.String())
+	defer file.Close()
+
+	// the size is only a hint, as the file may change while being read
+	buffer := bytes.NewBuffer(make([]byte, 0, info.Size()+bytes.MinRead))
+	if _, err := buffer.ReadFrom(file); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return buffer.Bytes(), nil
 }
 
 // ReadDir reads the directory named by dirname and returns
 // a list of directory entries sorted by filename.
 func (p Path) ReadDir() ([]Path, error) {
-	if !p.DirExists() {
-		return nil, ErrDirDoesNotExist
+	if _, err := statDir("readdir", p); err != nil {
+		return nil, err
 	}
-	osFiles, err := ioutil.ReadDir(p.String())
-	paths := make([]Path, len(osFiles))
+	entries, err := os.ReadDir(p.String())
+	paths := make([]Path, len(entries))
 
 	if err != nil {
-		return paths, err
+		return paths, wrapError(err)
 	}
 
-	for i := range osFiles {
-		paths[i] = Path(osFiles[i].Name())
+	for i := range entries {
+		paths[i] = Path(entries[i].Name())
 	}
 
 	return paths, nil
@@ -136,7 +192,7 @@ func (p Path) ReadDir() ([]Path, error) {
 // a given destination. If the receiver is a directory, a recursive copy of
 // its contents is made.
 func (p Path) CopyTo(dest Path) error {
-	return copy(p, dest)
+	return p.CopyToWithOptions(dest, CopyOptions{})
 }
 
 // Join join the current path with the specified string value
@@ -181,37 +237,10 @@ func (p Path) Clean() Path {
 	return Path(filepath.Clean(p.String()))
 }
 
-// Walk walks on every item (configurable by the 'walkType') parameter and call
-// the walker function.
+// Walk walks recursively on every item (configurable by the 'walkType') parameter
+// and call the walker function. Entries are visited in lexical order.
 func (p Path) Walk(walkType WalkType, walker func(path Path, isDirectory bool) error) error {
-	if !p.DirExists() {
-		return ErrDirDoesNotExist
-	}
-
-	return filepath.Walk(p.String(), func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if path == p.String() {
-			return nil
-		}
-
-		skipper := filepath.SkipDir
-		if Dirname(path) == p.String() {
-			skipper = nil
-		}
-
-		if info.IsDir() && walkType == WalkFiles {
-			return skipper
-		}
-
-		if !info.IsDir() && walkType == WalkDirs {
-			return skipper
-		}
-
-		return walker(Path(path), info.IsDir())
-	})
+	return p.WalkWithOptions(WalkOptions{Type: walkType, Sort: SortByName}, walker)
 }
 
 // Abs returns an absolute representation of path, when possible
@@ -222,109 +251,49 @@ func (p Path) Abs() Path {
 	return p
 }
 
+// open opens the file with the given flags, using DefaultFilesystem to create
+// its parents when needed.
 func open(p Path, flag int, mode os.FileMode) (*os.File, error) {
-	if p.Empty() {
-		return nil, ErrPathIsEmpty
-	}
-
-	if p.DirExists() {
-		return nil, ErrPathIsDirectory
-	}
-
-	file, err := os.OpenFile(p.String(), flag, mode)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, err
-		}
-		if err = p.Clean().Parent().MkdirAll(); err != nil {
-			return nil, err
-		}
-		return os.OpenFile(p.String(), flag, mode)
-	}
-
-	return file, nil
+	return DefaultFilesystem.open(p, flag, mode)
 }
 
-// copy copy one path to another
-func copy(src, dest Path) error {
-	if !src.Exists() {
-		return ErrNotFound
-	}
-
-	if src.FileExists() {
-		if dest.DirExists() {
-			return copyFiles(src, dest.Join(src.Basename()))
-		}
-		return copyFiles(src, dest)
-	}
-
-	if dest.FileExists() {
-		return ErrPathIsDirectoryDestFile
-	}
-
-	return copyDirs(src, dest)
-}
-
-// copyDirs copy one dir to another
-func copyDirs(src, dest Path) error {
-	if !dest.DirExists() {
-		if err := dest.MkdirAll(); err != nil {
-			return err
-		}
+// Count how many files are on some path 'p'
+func (p Path) Count(walkType WalkType) (count uint64) {
+	if !p.DirExists() {
+		return
 	}
-	return src.Walk(WalkBoth, func(path Path, isDirectory bool) error {
-		newDest := Path(strings.Replace(path.String(), src.String(), dest.String(), 1))
-
-		if isDirectory {
-			if err := newDest.MkdirAll(); err != nil {
-				return err
-			}
-		} else {
-			if err := copyFiles(path, newDest); err != nil {
-				return err
-			}
-		}
 
+	opts := ParallelWalkOptions{WalkOptions: WalkOptions{Type: walkType}}
+	p.ParallelWalk(opts, func(entry *DirEntry) error { // nolint: errcheck
+		atomic.AddUint64(&count, 1)
 		return nil
 	})
+	return
 }
 
-// copyFiles copy one file to another
-func copyFiles(src, dest Path) error {
-	info := src.Info()
-	if info == nil {
-		return ErrFileDoesNotExist
-	}
-
-	srcFile, err := open(src, openFileFlag, 0400) //r--------
+// DiskUsage returns the sum of the sizes of all files under the path 'p'.
+// When 'p' is a file, its own size is returned.
+func (p Path) DiskUsage() (int64, error) {
+	info, err := stat("stat", p, ErrNotFound)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	defer srcFile.Close()
 
-	destFile, err := open(dest, createFileFlag, info.Mode())
-	if err != nil {
-		return err
-	}
-	defer destFile.Close()
-
-	_, err = io.Copy(destFile, srcFile)
-	if err != nil {
-		return err
+	if !info.IsDir() {
+		return info.Size(), nil
 	}
 
-	return nil
-}
-
-// Count how many files are on some path 'p'
-func (p Path) Count(walkType WalkType) (count uint64) {
-	if !p.DirExists() {
-		return
-	}
+	var usage int64
+	opts := ParallelWalkOptions{WalkOptions: WalkOptions{Type: WalkFiles}}
+	err = p.ParallelWalk(opts, func(entry *DirEntry) error {
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
 
-	p.Walk(walkType, func(path Path, isDirectory bool) error { // nolint: errcheck
-		count++
+		atomic.AddInt64(&usage, info.Size())
 		return nil
 	})
-	return
+
+	return usage, err
 }
@@ -2,8 +2,10 @@ package fs_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
 
@@ -284,13 +286,44 @@ func TestWalkErrDirExists(t *testing.T) {
 
 		for i, test := range tests {
 			received := test.path.Walk(fs.WalkBoth, func(path fs.Path, isDirectory bool) error { return nil })
-			if received != test.expected {
+			if !errors.Is(received, test.expected) {
 				t.Errorf("Case %d, error testing walk Directory Doesn't Exists: expected '%v' received '%v' ", i, test.expected, received)
 			}
 		}
 	})
 }
 
+func TestNotDirectoryErrors(t *testing.T) {
+	WithTempDir(func(root string) {
+		file := fs.Path(root).Join("file.txt")
+		if err := file.Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+
+		tests := []func(p fs.Path) error{
+			func(p fs.Path) error { return p.Walk(fs.WalkBoth, func(fs.Path, bool) error { return nil }) },
+			func(p fs.Path) error {
+				return p.ParallelWalk(fs.ParallelWalkOptions{}, func(*fs.DirEntry) error { return nil })
+			},
+			func(p fs.Path) error { w := p.Walker(fs.WalkerOptions{}); w.Next(); return w.Err() },
+			func(p fs.Path) error { _, err := p.ReadDir(); return err },
+			func(p fs.Path) error { _, err := p.ReadDirEntries(fs.ReadDirOptions{}); return err },
+		}
+
+		// existing files aren't reported as missing, unlike missing paths
+		for i, test := range tests {
+			if err := test(file); !errors.Is(err, fs.ErrNotDirectory) || errors.Is(err, os.ErrNotExist) {
+				t.Errorf("Case %d, error testing file: expected '%v', received '%v'", i, fs.ErrNotDirectory, err)
+			}
+
+			if err := test(file.Join("../missing")); !errors.Is(err, fs.ErrNotFound) || errors.Is(err, fs.ErrNotDirectory) {
+				t.Errorf("Case %d, error testing missing path: expected '%v', received '%v'", i, fs.ErrNotFound, err)
+			}
+		}
+	})
+}
+
 func TestWalkErrFunc(t *testing.T) {
 	WithTempDir(func(root string) {
 		basePath := fs.Path(root)
@@ -487,7 +520,7 @@ func TestCopyToPathErrors(t *testing.T) {
 		}
 
 		for i, test := range tests {
-			if err := test.source.CopyTo(test.destination); err != test.expected {
+			if err := test.source.CopyTo(test.destination); !errors.Is(err, test.expected) {
 				t.Errorf("Case %d, error testing copy: expected '%v', received '%v'", i, test.expected, err)
 			}
 
@@ -495,6 +528,42 @@ func TestCopyToPathErrors(t *testing.T) {
 	})
 }
 
+func TestPermissionDeniedErrors(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not checked for the superuser")
+	}
+
+	WithTempDir(func(root string) {
+		locked := fs.Path(root).Join("locked")
+		dir := locked.Join("dir")
+		if err := dir.MkdirAll(); err != nil {
+			t.Fatalf("Error creating a directory to test: %v", err)
+		}
+		if err := locked.Chmod(0); err != nil {
+			t.Fatalf("Error changing the mode of a directory to test: %v", err)
+		}
+		defer locked.Chmod(0700)
+
+		tests := []func() error{
+			func() error { return dir.WalkDir(fs.WalkOptions{}, func(*fs.DirEntry) error { return nil }) },
+			func() error {
+				return dir.ParallelWalk(fs.ParallelWalkOptions{}, func(*fs.DirEntry) error { return nil })
+			},
+			func() error { w := dir.Walker(fs.WalkerOptions{}); w.Next(); return w.Err() },
+			func() error { _, err := dir.ReadDir(); return err },
+			func() error { _, err := dir.ReadDirEntries(fs.ReadDirOptions{}); return err },
+			func() error { _, err := dir.DiskUsage(); return err },
+			func() error { return dir.CopyTo(fs.Path(root).Join("copy")) },
+		}
+
+		for i, test := range tests {
+			if err := test(); !errors.Is(err, fs.ErrPermissionDenied) || errors.Is(err, fs.ErrNotFound) {
+				t.Errorf("Case %d, error testing permission denied: expected '%v', received '%v'", i, fs.ErrPermissionDenied, err)
+			}
+		}
+	})
+}
+
 func TestCopyToPathFiles(t *testing.T) {
 	WithTempDir(func(root string) {
 		basePath := fs.Path(root)
@@ -530,7 +599,7 @@ func TestCopyToPathFiles(t *testing.T) {
 				t.Errorf("Case %d, error writing to file: %v", i, err)
 			}
 
-			if err := test.source.CopyTo(test.destination); err != test.expected {
+			if err := test.source.CopyTo(test.destination); !errors.Is(err, test.expected) {
 				t.Errorf("Case %d, error testing copy: expected '%v', received '%v'", i, test.expected, err)
 			}
 
@@ -794,19 +863,19 @@ func TestCreateAppendErr(t *testing.T) {
 		for i, test := range tests {
 			path := test.path
 
-			if _, err := path.Create(); err != test.expected {
+			if _, err := path.Create(); !errors.Is(err, test.expected) {
 				t.Errorf("Case %d, error testing create: expected '%v', received '%v'", i, test.expected, err)
 			}
 
-			if _, err := fs.Create(path.String()); err != test.expected {
+			if _, err := fs.Create(path.String()); !errors.Is(err, test.expected) {
 				t.Errorf("Case %d, error testing open: expected '%v', received '%v'", i, test.expected, err)
 			}
 
-			if _, err := path.Append(); err != test.expected {
+			if _, err := path.Append(); !errors.Is(err, test.expected) {
 				t.Errorf("Case %d, error testing create: expected '%v', received '%v'", i, test.expected, err)
 			}
 
-			if _, err := fs.Append(path.String()); err != test.expected {
+			if _, err := fs.Append(path.String()); !errors.Is(err, test.expected) {
 				t.Errorf("Case %d, error testing open: expected '%v', received '%v'", i, test.expected, err)
 			}
 		}
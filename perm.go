@@ -0,0 +1,330 @@
+package fs
+
+import (
+	"os"
+	"strconv"
+)
+
+// permBits are the permission bits of a mode, along with the special bits
+// that can be changed by Chmod.
+const permBits = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+
+// Mode returns the mode bits of the path
+func (p Path) Mode() (os.FileMode, error) {
+	info, err := os.Stat(p.String())
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return info.Mode(), nil
+}
+
+// Owner returns the numeric user and group ids of the owner of the path. They
+// are only available on Unix systems, failing with ErrNotSupported elsewhere.
+func (p Path) Owner() (uid, gid int, err error) {
+	info, err := os.Stat(p.String())
+	if err != nil {
+		return -1, -1, wrapError(err)
+	}
+
+	uid, gid, ok := ownerOf(info)
+	if !ok {
+		return -1, -1, newError("stat", p, ErrNotSupported)
+	}
+	return uid, gid, nil
+}
+
+// Chmod changes the mode of the path
+func (p Path) Chmod(mode os.FileMode) error {
+	return wrapError(os.Chmod(p.String(), mode))
+}
+
+// ChmodString changes the mode of the path as described by a mode string, as
+// accepted by ParseMode.
+func (p Path) ChmodString(mode string) error {
+	m, err := ParseMode(mode)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(p.String())
+	if err != nil {
+		return wrapError(err)
+	}
+
+	return p.Chmod(m.Apply(info.Mode(), info.IsDir()))
+}
+
+// Chown changes the numeric user and group ids of the path. A id of -1 keeps
+// the current one.
+func (p Path) Chown(uid, gid int) error {
+	return wrapError(os.Chown(p.String(), uid, gid))
+}
+
+// ChmodAll changes the mode of the path and, when it is a directory, of
+// everything inside it, using fileMode for files and dirMode for directories.
+// Symbolic links are not followed.
+func (p Path) ChmodAll(fileMode, dirMode os.FileMode) error {
+	return p.chmodAll(func(mode os.FileMode, isDir bool) os.FileMode {
+		if isDir {
+			return dirMode
+		}
+		return fileMode
+	})
+}
+
+// ChmodAllString changes the mode of the path and, when it is a directory, of
+// everything inside it, as described by a mode string, as accepted by ParseMode.
+// The X permission makes `u=rwX` work like in `chmod -R`. Symbolic links are not
+// followed.
+func (p Path) ChmodAllString(mode string) error {
+	m, err := ParseMode(mode)
+	if err != nil {
+		return err
+	}
+	return p.chmodAll(m.Apply)
+}
+
+// ChownAll changes the numeric user and group ids of the path and, when it is a
+// directory, of everything inside it. Symbolic links are changed themselves,
+// instead of the paths they point to.
+func (p Path) ChownAll(uid, gid int) error {
+	if err := p.Chown(uid, gid); err != nil {
+		return err
+	}
+
+	if info, err := stat("chown", p, ErrNotFound); err != nil || !info.IsDir() {
+		return err
+	}
+
+	return p.WalkDir(WalkOptions{}, func(entry *DirEntry) error {
+		return wrapError(os.Lchown(entry.Path().String(), uid, gid))
+	})
+}
+
+// chmodAll changes the mode of the path and everything inside it to the one
+// returned by change. The directories are changed before their content, keeping
+// the owner permissions needed to read them, which are removed after their
+// content was changed.
+func (p Path) chmodAll(change func(mode os.FileMode, isDir bool) os.FileMode) error {
+	info, err := os.Stat(p.String())
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if !info.IsDir() {
+		return p.Chmod(change(info.Mode(), false))
+	}
+
+	type dirMode struct {
+		path Path
+		mode os.FileMode
+	}
+	var dirs []dirMode
+
+	chmodDir := func(path Path, info os.FileInfo) error {
+		mode := change(info.Mode(), true)
+
+		// the owner must be able to list the directory while walking it
+		walkable := mode | info.Mode().Perm()&0500
+		if walkable != mode {
+			dirs = append(dirs, dirMode{path: path, mode: mode})
+		}
+		return path.Chmod(walkable)
+	}
+
+	if err := chmodDir(p, info); err != nil {
+		return err
+	}
+
+	err = p.WalkDir(WalkOptions{}, func(entry *DirEntry) error {
+		if entry.IsSymlink() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return chmodDir(entry.Path(), info)
+		}
+		return entry.Path().Chmod(change(info.Mode(), false))
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := dirs[i].path.Chmod(dirs[i].mode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SymbolicMode is a change of mode, as described by a mode string
+type SymbolicMode struct {
+	clauses []modeClause
+}
+
+// modeClause is a single operation of a symbolic mode, like `g+w`
+type modeClause struct {
+	// who are the bits that can be changed by the operation
+	who os.FileMode
+
+	// op is one of '+', '-' and '='
+	op byte
+
+	// perm are the bits given to the operation
+	perm os.FileMode
+
+	// execIfX adds the execute bits for directories and for files executable
+	// by anyone, i.e. the X permission.
+	execIfX bool
+
+	// copyFrom, when not zero, is one of 'u', 'g' and 'o', whose current
+	// permissions are given to the operation.
+	copyFrom byte
+}
+
+// ParseMode parses a mode string, either a octal number like `0755`, or a
+// comma separated list of symbolic changes, like `u+x,go-w` or `a=rX`, as
+// described by chmod(1). The umask is not taken into account.
+func ParseMode(mode string) (SymbolicMode, error) {
+	invalid := newError("parsemode", "", ErrInvalidMode)
+
+	if mode != "" && mode[0] >= '0' && mode[0] <= '7' {
+		n, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || n > 07777 {
+			return SymbolicMode{}, invalid
+		}
+		return SymbolicMode{clauses: []modeClause{{who: permBits, op: '=', perm: fromOctal(n)}}}, nil
+	}
+
+	var m SymbolicMode
+	for i := 0; i <= len(mode); i++ {
+		// each clause starts with who it applies to
+		var who os.FileMode
+		for ; i < len(mode) && !isModeOp(mode[i]); i++ {
+			switch mode[i] {
+			case 'u':
+				who |= 0700 | os.ModeSetuid
+			case 'g':
+				who |= 0070 | os.ModeSetgid
+			case 'o':
+				who |= 0007 | os.ModeSticky
+			case 'a':
+				who |= permBits
+			default:
+				return SymbolicMode{}, invalid
+			}
+		}
+		if who == 0 {
+			who = permBits
+		}
+
+		// followed by one or more operations
+		if i == len(mode) {
+			return SymbolicMode{}, invalid
+		}
+		for ; i < len(mode) && mode[i] != ','; i++ {
+			c := modeClause{who: who, op: mode[i]}
+
+			letters := 0
+			for ; i+1 < len(mode) && mode[i+1] != ',' && !isModeOp(mode[i+1]); i++ {
+				letters++
+
+				switch mode[i+1] {
+				case 'r':
+					c.perm |= 0444
+				case 'w':
+					c.perm |= 0222
+				case 'x':
+					c.perm |= 0111
+				case 'X':
+					c.execIfX = true
+				case 's':
+					c.perm |= os.ModeSetuid | os.ModeSetgid
+				case 't':
+					c.perm |= os.ModeSticky
+				case 'u', 'g', 'o':
+					c.copyFrom = mode[i+1]
+				default:
+					return SymbolicMode{}, invalid
+				}
+			}
+
+			// the permissions of someone else can't be mixed with other ones
+			if c.copyFrom != 0 && letters > 1 {
+				return SymbolicMode{}, invalid
+			}
+
+			m.clauses = append(m.clauses, c)
+		}
+	}
+
+	return m, nil
+}
+
+// Apply returns the mode resulting of changing the given one, which belongs to
+// a directory when isDir is true.
+func (m SymbolicMode) Apply(mode os.FileMode, isDir bool) os.FileMode {
+	for _, c := range m.clauses {
+		perm := c.perm
+
+		if c.execIfX && (isDir || mode&0111 != 0) {
+			perm |= 0111
+		}
+
+		switch c.copyFrom {
+		case 'u':
+			perm = spreadPerm(mode >> 6)
+		case 'g':
+			perm = spreadPerm(mode >> 3)
+		case 'o':
+			perm = spreadPerm(mode)
+		}
+
+		perm &= c.who
+
+		switch c.op {
+		case '+':
+			mode |= perm
+		case '-':
+			mode &^= perm
+		case '=':
+			mode = mode&^c.who | perm
+		}
+	}
+
+	return mode
+}
+
+// isModeOp returns true when the character is a operation of a mode string
+func isModeOp(c byte) bool {
+	return c == '+' || c == '-' || c == '='
+}
+
+// spreadPerm returns the lowest three permission bits of the mode given to the
+// user, group and others.
+func spreadPerm(mode os.FileMode) os.FileMode {
+	mode &= 07
+	return mode<<6 | mode<<3 | mode
+}
+
+// fromOctal converts a octal mode, as used by chmod(1), to a os.FileMode
+func fromOctal(n uint64) os.FileMode {
+	mode := os.FileMode(n) & os.ModePerm
+	if n&04000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if n&02000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if n&01000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
@@ -0,0 +1,277 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		mode     string
+		current  os.FileMode
+		isDir    bool
+		expected os.FileMode
+		err      error
+	}{
+		{mode: "0755", current: 0600, expected: 0755},
+		{mode: "644", current: 0777, expected: 0644},
+		{mode: "4755", current: 0600, expected: 0755 | os.ModeSetuid},
+		{mode: "u+x", current: 0644, expected: 0744},
+		{mode: "u+x,go-w", current: 0666, expected: 0744},
+		{mode: "a=r", current: 0777, expected: 0444},
+		{mode: "=rw", current: 0111, expected: 0666},
+		{mode: "+x", current: 0644, expected: 0755},
+		{mode: "go=", current: 0777, expected: 0700},
+		{mode: "u=rwX", current: 0444, expected: 0644},
+		{mode: "u=rwX", current: 0454, expected: 0754},
+		{mode: "u=rwX", current: 0444, isDir: true, expected: 0744 | os.ModeDir},
+		{mode: "g=u", current: 0640, expected: 0660},
+		{mode: "o=g", current: 0650, expected: 0655},
+		{mode: "u-w+x", current: 0644, expected: 0544},
+		{mode: "u+s,+t", current: 0755, expected: 0755 | os.ModeSetuid | os.ModeSticky},
+		{mode: "", err: fs.ErrInvalidMode},
+		{mode: "u", err: fs.ErrInvalidMode},
+		{mode: "u+x,", err: fs.ErrInvalidMode},
+		{mode: "u+y", err: fs.ErrInvalidMode},
+		{mode: "k+x", err: fs.ErrInvalidMode},
+		{mode: "g=ur", err: fs.ErrInvalidMode},
+		{mode: "0788", err: fs.ErrInvalidMode},
+		{mode: "17777", err: fs.ErrInvalidMode},
+	}
+
+	for i, test := range tests {
+		m, err := fs.ParseMode(test.mode)
+		if !errors.Is(err, test.err) {
+			t.Errorf("Case %d, error testing parse mode '%s': expected '%v', received '%v'", i, test.mode, test.err, err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		current := test.current
+		if test.isDir {
+			current |= os.ModeDir
+		}
+
+		if received := m.Apply(current, test.isDir); received != test.expected {
+			t.Errorf("Case %d, error testing mode '%s': expected '%v', received '%v'", i, test.mode, test.expected, received)
+		}
+	}
+}
+
+func TestChmod(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		file := root.Join("file.txt")
+
+		if err := file.Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+
+		tests := []struct {
+			chmod    func() error
+			expected os.FileMode
+		}{
+			{chmod: func() error { return file.Chmod(0600) }, expected: 0600},
+			{chmod: func() error { return file.ChmodString("g+r,o+r") }, expected: 0644},
+			{chmod: func() error { return file.ChmodString("a+X") }, expected: 0644},
+			{chmod: func() error { return file.ChmodString("u+x,a+X") }, expected: 0755},
+			{chmod: func() error { return file.ChmodString("711") }, expected: 0711},
+		}
+
+		for i, test := range tests {
+			if err := test.chmod(); err != nil {
+				t.Errorf("Case %d, error changing mode: %v", i, err)
+				continue
+			}
+
+			if mode, err := file.Mode(); err != nil || mode != test.expected {
+				t.Errorf("Case %d, error testing chmod: expected '%v', received '%v' (%v)", i, test.expected, mode, err)
+			}
+		}
+
+		missing := root.Join("missing")
+		errs := []error{
+			missing.Chmod(0644),
+			missing.ChmodString("u+x"),
+			missing.ChmodAll(0644, 0755),
+			missing.Chown(-1, -1),
+			errorOf(missing.Mode()),
+		}
+
+		for i, err := range errs {
+			if !errors.Is(err, fs.ErrNotFound) {
+				t.Errorf("Case %d, error testing permissions of a missing path: expected '%v', received '%v'", i, fs.ErrNotFound, err)
+			}
+		}
+
+		if err := file.ChmodString("u+y"); !errors.Is(err, fs.ErrInvalidMode) {
+			t.Errorf("Error testing invalid mode: expected '%v', received '%v'", fs.ErrInvalidMode, err)
+		}
+	})
+}
+
+func TestChmodAll(t *testing.T) {
+	WithTempDir(func(dir string) {
+		tree := fs.Path(dir).Join("tree")
+
+		if err := createWalkTree(tree); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		if err := tree.Join("b/d/e/f.txt").Chmod(0755); err != nil {
+			t.Errorf("Error changing mode: %v", err)
+			return
+		}
+
+		if err := tree.ChmodAll(0600, 0700); err != nil {
+			t.Errorf("Error changing modes: %v", err)
+		}
+
+		modes := map[string]os.FileMode{
+			"":            os.ModeDir | 0700,
+			"a.txt":       0600,
+			"b":           os.ModeDir | 0700,
+			"b/d/e":       os.ModeDir | 0700,
+			"b/d/e/f.txt": 0600,
+		}
+
+		for path, expected := range modes {
+			if mode, err := tree.Join(path).Mode(); err != nil || mode != expected {
+				t.Errorf("Error testing chmod all of '%s': expected '%v', received '%v' (%v)", path, expected, mode, err)
+			}
+		}
+
+		// executable files keep being executable with X
+		if err := tree.Join("b/d/e/f.txt").Chmod(0700); err != nil {
+			t.Errorf("Error changing mode: %v", err)
+			return
+		}
+
+		if err := tree.ChmodAllString("u=rwX,go=rX"); err != nil {
+			t.Errorf("Error changing modes: %v", err)
+		}
+
+		modes = map[string]os.FileMode{
+			"":            os.ModeDir | 0755,
+			"a.txt":       0644,
+			"b":           os.ModeDir | 0755,
+			"b/d/e":       os.ModeDir | 0755,
+			"b/d/e/f.txt": 0755,
+		}
+
+		for path, expected := range modes {
+			if mode, err := tree.Join(path).Mode(); err != nil || mode != expected {
+				t.Errorf("Error testing chmod all of '%s': expected '%v', received '%v' (%v)", path, expected, mode, err)
+			}
+		}
+
+		// removing the permissions of the directories after their content
+		if err := tree.ChmodAllString("a-x"); err != nil {
+			t.Errorf("Error removing permissions: %v", err)
+		}
+		_ = tree.ChmodAll(0644, 0755)
+	})
+}
+
+func TestChmodAllUnreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not checked for the superuser")
+	}
+
+	WithTempDir(func(dir string) {
+		tree := fs.Path(dir).Join("tree")
+
+		if err := createWalkTree(tree); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+		defer tree.ChmodAll(0644, 0755)
+
+		tests := []struct {
+			modes    map[string]os.FileMode
+			mode     string
+			expected map[string]os.FileMode
+		}{
+			{
+				modes: map[string]os.FileMode{"b/d/e": 0600, "b": 0300},
+				mode:  "u=rwX",
+				expected: map[string]os.FileMode{
+					"":            os.ModeDir | 0755,
+					"b":           os.ModeDir | 0700,
+					"b/d/e":       os.ModeDir | 0700,
+					"b/d/e/f.txt": 0644,
+				},
+			},
+			{
+				mode: "u=r,go=",
+				expected: map[string]os.FileMode{
+					"": os.ModeDir | 0400,
+				},
+			},
+			{
+				mode: "u+rwX",
+				expected: map[string]os.FileMode{
+					"":            os.ModeDir | 0700,
+					"b":           os.ModeDir | 0700,
+					"b/d":         os.ModeDir | 0700,
+					"b/d/e":       os.ModeDir | 0700,
+					"b/d/e/f.txt": 0600,
+				},
+			},
+		}
+
+		for i, test := range tests {
+			for path, mode := range test.modes {
+				if err := tree.Join(path).Chmod(mode); err != nil {
+					t.Errorf("Case %d, error changing mode: %v", i, err)
+				}
+			}
+
+			if err := tree.ChmodAllString(test.mode); err != nil {
+				t.Errorf("Case %d, error changing modes: %v", i, err)
+			}
+
+			for path, expected := range test.expected {
+				if mode, err := tree.Join(path).Mode(); err != nil || mode != expected {
+					t.Errorf("Case %d, error testing chmod all of '%s': expected '%v', received '%v' (%v)", i, path, expected, mode, err)
+				}
+			}
+		}
+	})
+}
+
+func TestOwner(t *testing.T) {
+	WithTempDir(func(dir string) {
+		tree := fs.Path(dir).Join("tree")
+
+		if err := createWalkTree(tree); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		uid, gid, err := tree.Join("a.txt").Owner()
+		if errors.Is(err, fs.ErrNotSupported) {
+			t.Skip("owners are not supported")
+		}
+
+		if err != nil || uid != os.Getuid() || gid != os.Getgid() {
+			t.Errorf("Error testing owner: expected '%d:%d', received '%d:%d' (%v)", os.Getuid(), os.Getgid(), uid, gid, err)
+		}
+
+		if err := tree.ChownAll(os.Getuid(), os.Getgid()); err != nil {
+			t.Errorf("Error testing chown all: %v", err)
+		}
+
+		if _, _, err := tree.Join("missing").Owner(); !errors.Is(err, fs.ErrNotFound) {
+			t.Errorf("Error testing owner of a missing path: expected '%v', received '%v'", fs.ErrNotFound, err)
+		}
+	})
+}
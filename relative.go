@@ -0,0 +1,112 @@
+package fs
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Rel returns a relative path that is lexically equivalent to the path when
+// joined to base, as described by filepath.Rel.
+func (p Path) Rel(base Path) (Path, error) {
+	rel, err := filepath.Rel(base.String(), p.String())
+	if err != nil {
+		return "", newError("rel", p, err)
+	}
+	return Path(rel), nil
+}
+
+// IsChildOf returns true when the path is inside the parent directory, at any
+// depth. The paths are compared lexically, after being made absolute, so
+// symbolic links are not evaluated. A path is not a child of itself.
+func (p Path) IsChildOf(parent Path) bool {
+	path, dir := p.Abs().Components(), parent.Abs().Components()
+	if len(path) <= len(dir) {
+		return false
+	}
+
+	for i := range dir {
+		if path[i] != dir[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAncestorOf returns true when the child is inside the path, at any depth, as
+// described by IsChildOf.
+func (p Path) IsAncestorOf(child Path) bool {
+	return child.IsChildOf(p)
+}
+
+// Components returns the elements of the clean path. The first element of an
+// absolute path is its root, like "/", and the current directory has no elements.
+func (p Path) Components() []string {
+	path := p.Clean().String()
+	if path == "." {
+		return nil
+	}
+
+	var components []string
+
+	root := filepath.VolumeName(path)
+	if strings.HasPrefix(path[len(root):], string(filepath.Separator)) {
+		root += string(filepath.Separator)
+	}
+	if root != "" {
+		components = append(components, root)
+		path = path[len(root):]
+	}
+
+	for _, component := range strings.Split(path, string(filepath.Separator)) {
+		if component != "" {
+			components = append(components, component)
+		}
+	}
+
+	return components
+}
+
+// CommonPrefix returns the longest path containing all the given paths, which
+// are compared lexically. The result is empty when there are no paths or when
+// they have nothing in common, like an absolute and a relative path.
+func CommonPrefix(paths ...Path) Path {
+	if len(paths) == 0 {
+		return ""
+	}
+
+	prefix := paths[0].Components()
+	for _, path := range paths[1:] {
+		components := path.Components()
+		if len(components) < len(prefix) {
+			prefix = prefix[:len(components)]
+		}
+
+		for i := range prefix {
+			if components[i] != prefix[i] {
+				prefix = prefix[:i]
+				break
+			}
+		}
+	}
+
+	if len(prefix) == 0 {
+		return ""
+	}
+	return Path(filepath.Join(prefix...))
+}
+
+// Rebase returns the path moved from the old root directory to the new one,
+// keeping its position relative to the root. The path must be the old root or
+// be inside of it.
+func (p Path) Rebase(oldRoot, newRoot Path) (Path, error) {
+	if p.Abs() != oldRoot.Abs() && !p.IsChildOf(oldRoot) {
+		return "", newError("rebase", p, ErrOutsideRoot)
+	}
+
+	rel, err := p.Abs().Rel(oldRoot.Abs())
+	if err != nil {
+		return "", err
+	}
+
+	return newRoot.JoinP(rel), nil
+}
@@ -0,0 +1,151 @@
+package fs_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestRel(t *testing.T) {
+	tests := []struct {
+		path     fs.Path
+		base     fs.Path
+		expected fs.Path
+		err      bool
+	}{
+		{path: "/a/b/c", base: "/a", expected: "b/c"},
+		{path: "/a", base: "/a", expected: "."},
+		{path: "/a/b", base: "/a/c", expected: "../b"},
+		{path: "a/b", base: "a", expected: "b"},
+		{path: "/a", base: "a", err: true},
+	}
+
+	for i, test := range tests {
+		received, err := test.path.Rel(test.base)
+		if (err != nil) != test.err || received != test.expected {
+			t.Errorf("Case %d, error testing rel: expected '%s', received '%s' (%v)", i, test.expected, received, err)
+		}
+	}
+}
+
+func TestIsChildOf(t *testing.T) {
+	tests := []struct {
+		path     fs.Path
+		parent   fs.Path
+		expected bool
+	}{
+		{path: "/a/b", parent: "/a", expected: true},
+		{path: "/a/b/c", parent: "/a", expected: true},
+		{path: "/a/b", parent: "/a/", expected: true},
+		{path: "/a", parent: "/", expected: true},
+		{path: "/a", parent: "/a", expected: false},
+		{path: "/ab", parent: "/a", expected: false},
+		{path: "/a", parent: "/a/b", expected: false},
+		{path: "/a/../b", parent: "/a", expected: false},
+		{path: "a/b", parent: "a", expected: true},
+		{path: "/", parent: "/", expected: false},
+	}
+
+	for i, test := range tests {
+		if received := test.path.IsChildOf(test.parent); received != test.expected {
+			t.Errorf("Case %d, error testing '%s' is child of '%s': expected '%v', received '%v'", i, test.path, test.parent, test.expected, received)
+		}
+
+		if received := test.parent.IsAncestorOf(test.path); received != test.expected {
+			t.Errorf("Case %d, error testing '%s' is ancestor of '%s': expected '%v', received '%v'", i, test.parent, test.path, test.expected, received)
+		}
+	}
+}
+
+func TestComponents(t *testing.T) {
+	tests := []struct {
+		path     fs.Path
+		expected []string
+	}{
+		{path: "/a/b/c", expected: []string{"/", "a", "b", "c"}},
+		{path: "a/b/", expected: []string{"a", "b"}},
+		{path: "a//b/../c", expected: []string{"a", "c"}},
+		{path: "/", expected: []string{"/"}},
+		{path: ".", expected: nil},
+		{path: "", expected: nil},
+		{path: "../a", expected: []string{"..", "a"}},
+	}
+
+	for i, test := range tests {
+		if received := test.path.Components(); !reflect.DeepEqual(received, test.expected) {
+			t.Errorf("Case %d, error testing components of '%s': expected '%v', received '%v'", i, test.path, test.expected, received)
+		}
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		paths    []fs.Path
+		expected fs.Path
+	}{
+		{paths: []fs.Path{"/a/b/c", "/a/b/d", "/a/b"}, expected: "/a/b"},
+		{paths: []fs.Path{"/a/bc", "/a/bd"}, expected: "/a"},
+		{paths: []fs.Path{"/a", "/b"}, expected: "/"},
+		{paths: []fs.Path{"a/b", "a/c"}, expected: "a"},
+		{paths: []fs.Path{"/a", "a"}, expected: ""},
+		{paths: []fs.Path{"/a/b"}, expected: "/a/b"},
+		{paths: nil, expected: ""},
+	}
+
+	for i, test := range tests {
+		if received := fs.CommonPrefix(test.paths...); received != test.expected {
+			t.Errorf("Case %d, error testing common prefix: expected '%s', received '%s'", i, test.expected, received)
+		}
+	}
+}
+
+func TestRebase(t *testing.T) {
+	tests := []struct {
+		path     fs.Path
+		oldRoot  fs.Path
+		newRoot  fs.Path
+		expected fs.Path
+		err      error
+	}{
+		{path: "/src/a/src/b", oldRoot: "/src", newRoot: "/dst", expected: "/dst/a/src/b"},
+		{path: "/data/src/x", oldRoot: "/data/src", newRoot: "/data/src/backup", expected: "/data/src/backup/x"},
+		{path: "/src", oldRoot: "/src", newRoot: "/dst", expected: "/dst"},
+		{path: "/src/a", oldRoot: "/src/", newRoot: "rel", expected: "rel/a"},
+		{path: "/srcx/a", oldRoot: "/src", newRoot: "/dst", err: fs.ErrOutsideRoot},
+		{path: "/other", oldRoot: "/src", newRoot: "/dst", err: fs.ErrOutsideRoot},
+	}
+
+	for i, test := range tests {
+		received, err := test.path.Rebase(test.oldRoot, test.newRoot)
+		if !errors.Is(err, test.err) || received != test.expected {
+			t.Errorf("Case %d, error testing rebase: expected '%s' (%v), received '%s' (%v)", i, test.expected, test.err, received, err)
+		}
+	}
+}
+
+func TestCopyToUncleanSource(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		file := root.Join("src/a/src/file.txt")
+		if err := file.Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+
+		// the paths walked are clean, so they don't start with the source
+		src := fs.Path(dir + "/./src/")
+		dest := root.Join("dest")
+
+		if err := src.CopyTo(dest); err != nil {
+			t.Errorf("Error copying: %v", err)
+			return
+		}
+
+		if expected := dest.Join("a/src/file.txt"); !expected.FileExists() {
+			t.Errorf("Error testing copy of unclean source: file '%s' should exist", expected)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ProtectedPaths are the paths that can't be removed, along with the directories
+// containing them. The home directory of the user and the current working
+// directory are always protected as well.
+var ProtectedPaths = []Path{
+	"/",
+	"/bin",
+	"/boot",
+	"/dev",
+	"/etc",
+	"/home",
+	"/lib",
+	"/lib64",
+	"/opt",
+	"/proc",
+	"/root",
+	"/sbin",
+	"/srv",
+	"/sys",
+	"/usr",
+	"/var",
+}
+
+// RemoveOptions configures the removal done by RemoveWithOptions
+type RemoveOptions struct {
+	// DryRun makes the removal only check the path, reporting what would be
+	// removed to the Report function without removing anything.
+	DryRun bool
+
+	// Report is called on a dry run for every path that would be removed
+	Report func(path Path)
+
+	// Root, when not empty, is the directory the removed path must be inside of
+	Root Path
+}
+
+// RemoveAll removes the files or directory in the given path. It refuses to
+// remove an empty path or a protected one, as described by ProtectedPaths.
+func (p Path) RemoveAll() error {
+	return p.RemoveWithOptions(RemoveOptions{})
+}
+
+// RemoveWithOptions works like RemoveAll, with the additional constraints and
+// behavior configured by the options.
+func (p Path) RemoveWithOptions(opts RemoveOptions) error {
+	if p.Empty() {
+		return newError("remove", p, ErrPathIsEmpty)
+	}
+
+	path, err := resolveParent(p)
+	if err != nil {
+		return newError("remove", p, err)
+	}
+
+	if isProtected(path) {
+		return newError("remove", p, ErrProtectedPath)
+	}
+
+	if !opts.Root.Empty() {
+		root, err := filepath.EvalSymlinks(opts.Root.Abs().String())
+		if err != nil {
+			return wrapError(err)
+		}
+
+		if !Path(path).IsChildOf(Path(root)) {
+			return newError("remove", p, ErrOutsideRoot)
+		}
+	}
+
+	// the dry run checks the same path removed, where a trailing separator
+	// doesn't make a link be followed
+	clean := p.Clean()
+	if opts.DryRun {
+		return dryRemove(clean, opts.Report)
+	}
+
+	return wrapError(os.RemoveAll(clean.String()))
+}
+
+// IsProtected returns true when the path is protected against removal
+func (p Path) IsProtected() bool {
+	path, err := resolveParent(p)
+	return err != nil || isProtected(path)
+}
+
+// resolveParent returns the absolute path with all the symbolic links in its
+// parent directories evaluated. The last element of the path is kept as it is,
+// as removing a link does not affect its target.
+func resolveParent(p Path) (string, error) {
+	abs, err := filepath.Abs(p.String())
+	if err != nil {
+		return "", err
+	}
+
+	dir, base := filepath.Split(abs)
+	if base == "" {
+		return abs, nil
+	}
+
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		// a missing parent has nothing to be removed, so it's kept as it is
+		if os.IsNotExist(err) {
+			return abs, nil
+		}
+		return "", err
+	}
+
+	return filepath.Join(dir, base), nil
+}
+
+// isProtected returns true when the absolute path is or contains a protected path
+func isProtected(path string) bool {
+	protected := append([]Path{}, ProtectedPaths...)
+
+	if home, err := os.UserHomeDir(); err == nil {
+		protected = append(protected, Path(home))
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		protected = append(protected, Path(wd))
+	}
+
+	for _, p := range protected {
+		target := p.Clean().String()
+		if resolved, err := filepath.EvalSymlinks(target); err == nil {
+			target = resolved
+		}
+
+		if path == target || Path(target).IsChildOf(Path(path)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dryRemove reports the paths that would be removed by RemoveAll
+func dryRemove(p Path, report func(path Path)) error {
+	if report == nil {
+		report = func(Path) {}
+	}
+
+	info, err := os.Lstat(p.String())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return wrapError(err)
+	}
+
+	if info.IsDir() {
+		if err := p.WalkDir(WalkOptions{Sort: SortByName}, func(entry *DirEntry) error {
+			report(entry.Path())
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	report(p)
+	return nil
+}
@@ -0,0 +1,160 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+// withProtected runs the handler with the path added to the protected ones
+func withProtected(path fs.Path, handler func()) {
+	previous := fs.ProtectedPaths
+	fs.ProtectedPaths = append(append([]fs.Path{}, previous...), path)
+	defer func() { fs.ProtectedPaths = previous }()
+
+	handler()
+}
+
+func TestRemoveAll(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createWalkTree(root.Join("tree")); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		home, _ := os.UserHomeDir()
+		wd, _ := os.Getwd()
+
+		protected := root.Join("protected")
+		if err := protected.Join("file.txt").Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+
+		// the paths outside the temporary directory are only removed on a dry
+		// run, so a failure of the guard can't remove them
+		tests := []struct {
+			path     fs.Path
+			dryRun   bool
+			expected error
+		}{
+			{path: root.Join("tree"), expected: nil},
+			{path: root.Join("missing"), expected: nil},
+			{path: "", expected: fs.ErrPathIsEmpty},
+			{path: "   ", expected: fs.ErrPathIsEmpty},
+			{path: protected, expected: fs.ErrProtectedPath},
+			{path: protected.Join(".."), expected: fs.ErrProtectedPath},
+			{path: "/", dryRun: true, expected: fs.ErrProtectedPath},
+			{path: "/usr/", dryRun: true, expected: fs.ErrProtectedPath},
+			{path: "/usr/../etc", dryRun: true, expected: fs.ErrProtectedPath},
+			{path: fs.Path(home), dryRun: true, expected: fs.ErrProtectedPath},
+			{path: fs.Path(wd), dryRun: true, expected: fs.ErrProtectedPath},
+			{path: ".", dryRun: true, expected: fs.ErrProtectedPath},
+			{path: "..", dryRun: true, expected: fs.ErrProtectedPath},
+		}
+
+		withProtected(protected, func() {
+			for i, test := range tests {
+				opts := fs.RemoveOptions{DryRun: test.dryRun}
+				if err := test.path.RemoveWithOptions(opts); !errors.Is(err, test.expected) {
+					t.Errorf("Case %d, error testing remove all: expected '%v', received '%v'", i, test.expected, err)
+				}
+			}
+		})
+
+		if root.Join("tree").Exists() {
+			t.Errorf("Path '%s' should have been removed", root.Join("tree"))
+		}
+
+		if !protected.Join("file.txt").Exists() {
+			t.Errorf("Path '%s' should have been kept", protected)
+		}
+	})
+}
+
+func TestRemoveWithOptions(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		tree := root.Join("tree")
+
+		if err := createWalkTree(tree); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		// a link inside the tree leading out of it
+		if err := os.Symlink(dir, tree.Join("out").String()); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		tests := []struct {
+			path     fs.Path
+			root     fs.Path
+			expected error
+		}{
+			{path: tree.Join("b"), root: tree, expected: nil},
+			{path: tree, root: tree, expected: fs.ErrOutsideRoot},
+			{path: root, root: tree, expected: fs.ErrOutsideRoot},
+			{path: tree.Join("../tree2"), root: tree, expected: fs.ErrOutsideRoot},
+			{path: tree.Join("out/other"), root: tree, expected: fs.ErrOutsideRoot},
+			{path: tree.Join("out"), root: tree, expected: nil},
+		}
+
+		for i, test := range tests {
+			var reported []fs.Path
+			opts := fs.RemoveOptions{
+				DryRun: true,
+				Root:   test.root,
+				Report: func(path fs.Path) { reported = append(reported, path) },
+			}
+
+			if err := test.path.RemoveWithOptions(opts); !errors.Is(err, test.expected) {
+				t.Errorf("Case %d, error testing remove with options: expected '%v', received '%v'", i, test.expected, err)
+			}
+
+			if test.expected != nil && len(reported) > 0 {
+				t.Errorf("Case %d, no paths should be reported on errors, received '%v'", i, reported)
+			}
+		}
+
+		var reported []fs.Path
+		opts := fs.RemoveOptions{DryRun: true, Report: func(path fs.Path) { reported = append(reported, path) }}
+		if err := tree.Join("b").RemoveWithOptions(opts); err != nil {
+			t.Errorf("Error on dry run: %v", err)
+		}
+
+		expected := []fs.Path{tree.Join("b/c.log"), tree.Join("b/d"), tree.Join("b/d/e"), tree.Join("b/d/e/f.txt"), tree.Join("b")}
+		if !reflect.DeepEqual(reported, expected) {
+			t.Errorf("Error testing dry run: expected '%v', received '%v'", expected, reported)
+		}
+
+		if !tree.Join("b/d/e/f.txt").Exists() {
+			t.Errorf("A dry run should not remove anything")
+		}
+
+		// a trailing separator doesn't make the link be followed
+		reported = nil
+		if err := fs.Path(tree.Join("out").String() + "/").RemoveWithOptions(opts); err != nil {
+			t.Errorf("Error on dry run: %v", err)
+		}
+
+		expected = []fs.Path{tree.Join("out")}
+		if !reflect.DeepEqual(reported, expected) {
+			t.Errorf("Error testing dry run of a link: expected '%v', received '%v'", expected, reported)
+		}
+
+		if err := tree.Join("out").RemoveWithOptions(fs.RemoveOptions{Root: tree}); err != nil {
+			t.Errorf("Error removing link: %v", err)
+		}
+
+		if !tree.Exists() || tree.Join("out").Exists() {
+			t.Errorf("Only the link should have been removed")
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package fs
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultMaxNameLength is the length in bytes of the longest name allowed by
+// most filesystems.
+const defaultMaxNameLength = 255
+
+// reservedNames are the names of devices on Windows, which can't be used as
+// file names, even with an extension.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SanitizeOptions configures the sanitization done by SanitizeFilename
+type SanitizeOptions struct {
+	// Replacement replaces every character not allowed. When empty, those
+	// characters are removed.
+	Replacement string
+
+	// MaxLength is the length in bytes of the longest name returned. Zero
+	// means 255 bytes, the limit of most filesystems.
+	MaxLength int
+}
+
+// SanitizeFilename returns a name that can be used for a file on any platform,
+// built from the given string. Control characters, path separators, invalid
+// UTF-8 and the characters not allowed on Windows are replaced, as well as
+// trailing dots and spaces. Reserved names, like "CON" or "..", are prefixed
+// with "_". The name is truncated to the maximum length, keeping its extension
+// when possible and never splitting a UTF-8 character. A name with no valid
+// characters results in "_".
+func SanitizeFilename(s string, opts SanitizeOptions) string {
+	maxLength := opts.MaxLength
+	if maxLength <= 0 {
+		maxLength = defaultMaxNameLength
+	}
+
+	// the replacement can't add characters not allowed either
+	replacement := strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || !isFilenameRune(r) {
+			return -1
+		}
+		return r
+	}, opts.Replacement)
+
+	var b strings.Builder
+	for _, r := range s {
+		if r == utf8.RuneError || !isFilenameRune(r) {
+			b.WriteString(replacement)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	name := strings.TrimRight(b.String(), ". ")
+
+	if name == "" || name == "." || name == ".." {
+		name = "_" + name
+	}
+
+	// truncating can result in a reserved name, so they're checked after it,
+	// truncating again to make room for the prefix
+	name = truncateName(name, maxLength)
+	if isReservedName(name) {
+		name = truncateName("_"+name, maxLength)
+	}
+
+	return name
+}
+
+// isReservedName returns true when the name is the name of a device on
+// Windows, with or without an extension
+func isReservedName(name string) bool {
+	stem := name
+	if i := strings.IndexByte(stem, '.'); i >= 0 {
+		stem = stem[:i]
+	}
+	return reservedNames[strings.ToUpper(strings.TrimRight(stem, " "))]
+}
+
+// isFilenameRune returns true when the character can be used in a file name
+func isFilenameRune(r rune) bool {
+	if r < 0x20 || r == 0x7f || r >= 0x80 && r < 0xa0 {
+		return false
+	}
+	return !strings.ContainsRune(`/\<>:"|?*`, r)
+}
+
+// truncateName truncates the name to the given length in bytes, keeping its
+// extension when it takes at most half of it, without splitting characters.
+func truncateName(name string, length int) string {
+	if len(name) <= length {
+		return name
+	}
+
+	ext := filepath.Ext(name)
+	if len(ext) > length/2 || len(ext) == len(name) {
+		ext = ""
+	}
+
+	stem := name[:len(name)-len(ext)]
+	end := length - len(ext)
+	for end > 0 && !utf8.RuneStart(stem[end]) {
+		end--
+	}
+
+	stem = strings.TrimRight(stem[:end], ". ")
+	if stem == "" {
+		stem = "_"
+	}
+	return stem + ext
+}
@@ -0,0 +1,86 @@
+package fs_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     fs.SanitizeOptions
+		expected string
+	}{
+		{name: "report.pdf", expected: "report.pdf"},
+		{name: "../../etc/passwd", expected: "....etcpasswd"},
+		{name: "../../etc/passwd", opts: fs.SanitizeOptions{Replacement: "_"}, expected: ".._.._etc_passwd"},
+		{name: "a\x00b\nc\td\x7f", expected: "abcd"},
+		{name: `what? <yes>: "no" | maybe*`, opts: fs.SanitizeOptions{Replacement: "-"}, expected: "what- -yes-- -no- - maybe-"},
+		{name: `a\b`, opts: fs.SanitizeOptions{Replacement: "/"}, expected: "ab"},
+		{name: "name. . .", expected: "name"},
+		{name: "CON", expected: "_CON"},
+		{name: "con.txt", expected: "_con.txt"},
+		{name: "Lpt1", expected: "_Lpt1"},
+		{name: "CONSOLE", expected: "CONSOLE"},
+		{name: ".", expected: "_"},
+		{name: "..", expected: "_"},
+		{name: "", expected: "_"},
+		{name: "///", expected: "_"},
+		{name: ".bashrc", expected: ".bashrc"},
+		{name: "invalid\xffutf8", expected: "invalidutf8"},
+		{name: "ünïcödé.txt", expected: "ünïcödé.txt"},
+		{name: "abcdefghij.txt", opts: fs.SanitizeOptions{MaxLength: 10}, expected: "abcdef.txt"},
+		{name: "abcdefghij", opts: fs.SanitizeOptions{MaxLength: 4}, expected: "abcd"},
+		{name: "ééééé.txt", opts: fs.SanitizeOptions{MaxLength: 8}, expected: "éé.txt"},
+		{name: "ab...cdefgh.txt", opts: fs.SanitizeOptions{MaxLength: 9}, expected: "ab.txt"},
+		{name: "a.verylongextension", opts: fs.SanitizeOptions{MaxLength: 8}, expected: "a.verylo"},
+		{name: "CONSOLE.md", opts: fs.SanitizeOptions{MaxLength: 6}, expected: "_CO.md"},
+		{name: "nullable", opts: fs.SanitizeOptions{MaxLength: 3}, expected: "_nu"},
+		{name: "COM1.txt", opts: fs.SanitizeOptions{MaxLength: 8}, expected: "_COM.txt"},
+		{name: strings.Repeat("x", 300), expected: strings.Repeat("x", 255)},
+	}
+
+	for i, test := range tests {
+		received := fs.SanitizeFilename(test.name, test.opts)
+		if received != test.expected {
+			t.Errorf("Case %d, error testing sanitize filename '%s': expected '%s', received '%s'", i, test.name, test.expected, received)
+		}
+
+		if !utf8.ValidString(received) {
+			t.Errorf("Case %d, error testing sanitize filename '%s': invalid UTF-8 '%s'", i, test.name, received)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		path     fs.Path
+		profile  fs.ValidationProfile
+		expected error
+	}{
+		{path: "/usr/lib/file.txt", profile: fs.PortableProfile, expected: nil},
+		{path: "/usr/lib/verylongfilename.txt", profile: fs.PortableProfile, expected: fs.ErrNameTooLong},
+		{path: "/usr/lib/verylongfilename.txt", profile: fs.LinuxProfile, expected: nil},
+		{path: "/usr/lib/my file.txt", profile: fs.PortableProfile, expected: fs.ErrInvalidName},
+		{path: "/usr/lib/my file.txt", profile: fs.LinuxProfile, expected: nil},
+		{path: "/usr/lib/-file", profile: fs.PortableProfile, expected: fs.ErrInvalidName},
+		{path: "/usr/lib/ünï", profile: fs.PortableProfile, expected: fs.ErrInvalidName},
+		{path: "a/../b/./c", profile: fs.PortableProfile, expected: nil},
+		{path: fs.Path(strings.Repeat("a/", 128)), profile: fs.PortableProfile, expected: fs.ErrPathTooLong},
+		{path: fs.Path(strings.Repeat("a", 256)), profile: fs.LinuxProfile, expected: fs.ErrNameTooLong},
+		{path: fs.Path(strings.Repeat("/aaaaaaaaa", 410)), profile: fs.LinuxProfile, expected: fs.ErrPathTooLong},
+		{path: "/usr/lib/a\x00b", profile: fs.LinuxProfile, expected: fs.ErrInvalidName},
+		{path: "", profile: fs.LinuxProfile, expected: fs.ErrPathIsEmpty},
+		{path: "/anything/goes here", profile: fs.ValidationProfile{}, expected: nil},
+	}
+
+	for i, test := range tests {
+		if err := test.path.Validate(test.profile); !errors.Is(err, test.expected) {
+			t.Errorf("Case %d, error testing validate: expected '%v', received '%v'", i, test.expected, err)
+		}
+	}
+}
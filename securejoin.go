@@ -0,0 +1,132 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// maxSymlinks is the number of symbolic links followed by SecureJoin before
+// considering the path a loop, like the limit of the kernel.
+const maxSymlinks = 255
+
+// lstat returns the information about the path, without following it when it
+// is a symbolic link
+var lstat = os.Lstat
+
+// EscapeError is the error returned by SecureJoin when the untrusted path,
+// either by itself or through symbolic links, leads outside the root directory.
+type EscapeError struct {
+	// Root is the directory the path should be inside of
+	Root Path
+
+	// Untrusted is the path given to SecureJoin
+	Untrusted string
+}
+
+// Error returns the message of the error
+func (e *EscapeError) Error() string {
+	return "securejoin " + e.Root.String() + ": path '" + e.Untrusted + "' escapes the root directory"
+}
+
+// Is allows the error to be compared to ErrOutsideRoot with errors.Is
+func (e *EscapeError) Is(target error) bool {
+	return target == ErrOutsideRoot
+}
+
+// SecureJoin joins the untrusted path to the receiver, a trusted root directory,
+// guaranteeing the result is inside of it. The path is resolved one component
+// at a time, following the symbolic links inside the root, and any `..` or
+// link leading outside of it results in a *EscapeError. Absolute paths, and
+// absolute link targets inside the root, are taken as relative to the root.
+// Once a component doesn't exist, the following ones are joined lexically,
+// without looking them up through it, until a `..` leaves it.
+//
+// The result is only guaranteed to be safe at the time it is resolved, as the
+// paths inside the root may be replaced afterwards by someone with access to it.
+func (p Path) SecureJoin(untrusted string) (Path, error) {
+	root := p.Clean()
+	escape := &EscapeError{Root: p, Untrusted: untrusted}
+
+	var resolved []string
+	pending := splitComponents(untrusted)
+
+	// missing is the position in resolved of the first component that doesn't
+	// exist, or -1 while every component resolved exists
+	missing := -1
+
+	for links := 0; len(pending) > 0; {
+		component := pending[0]
+		pending = pending[1:]
+
+		switch component {
+		case ".":
+			continue
+		case "..":
+			if len(resolved) == 0 {
+				return "", escape
+			}
+			resolved = resolved[:len(resolved)-1]
+			if len(resolved) <= missing {
+				missing = -1
+			}
+			continue
+		}
+
+		// nothing below a missing component is looked up, as it may be
+		// created meanwhile, as a link leading anywhere
+		if missing >= 0 {
+			resolved = append(resolved, component)
+			continue
+		}
+
+		path := root.Join(filepath.Join(append(resolved, component)...))
+
+		info, err := lstat(path.String())
+		if err != nil {
+			// the rest of the path doesn't exist, but may still escape lexically
+			if isNotFound(err) || errors.Is(err, syscall.ENOTDIR) {
+				missing = len(resolved)
+				resolved = append(resolved, component)
+				continue
+			}
+			return "", wrapError(err)
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = append(resolved, component)
+			continue
+		}
+
+		if links++; links > maxSymlinks {
+			return "", newError("securejoin", path, syscall.ELOOP)
+		}
+
+		target, err := path.Readlink()
+		if err != nil {
+			return "", err
+		}
+
+		// absolute targets are only followed when they are inside the root
+		if filepath.IsAbs(target.String()) {
+			rel, err := target.Rebase(root.Abs(), "")
+			if err != nil {
+				return "", escape
+			}
+			resolved, target = nil, rel
+		}
+
+		pending = append(splitComponents(target.String()), pending...)
+	}
+
+	return root.Join(filepath.Join(resolved...)), nil
+}
+
+// splitComponents splits a path into its components, without cleaning it
+func splitComponents(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == filepath.Separator
+	})
+}
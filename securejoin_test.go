@@ -0,0 +1,203 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestSecureJoin(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir).Join("root")
+		outside := fs.Path(dir).Join("outside")
+
+		for _, file := range []fs.Path{root.Join("a/b/file.txt"), outside.Join("secret")} {
+			if err := file.Touch(); err != nil {
+				t.Errorf("Error creating file: %v", err)
+				return
+			}
+		}
+
+		links := map[string]fs.Path{
+			"link-in":      "a/b",
+			"link-abs-in":  root.Join("a"),
+			"link-out":     "../outside",
+			"link-abs-out": outside,
+			"a/up":         "..",
+			"loop1":        "loop2",
+			"loop2":        "loop1",
+		}
+
+		for link, target := range links {
+			if err := root.Join(link).Symlink(target); err != nil {
+				t.Errorf("Error creating link: %v", err)
+				return
+			}
+		}
+
+		tests := []struct {
+			untrusted string
+			expected  fs.Path
+			err       error
+		}{
+			{untrusted: "a/b/file.txt", expected: root.Join("a/b/file.txt")},
+			{untrusted: "", expected: root},
+			{untrusted: "a/../a/./b", expected: root.Join("a/b")},
+			{untrusted: "/a/b", expected: root.Join("a/b")},
+			{untrusted: "link-in/file.txt", expected: root.Join("a/b/file.txt")},
+			{untrusted: "link-abs-in/b", expected: root.Join("a/b")},
+			{untrusted: "a/up/a", expected: root.Join("a")},
+			{untrusted: "missing/../a", expected: root.Join("a")},
+			{untrusted: "missing/file.txt", expected: root.Join("missing/file.txt")},
+			{untrusted: "missing/x/../../link-in/file.txt", expected: root.Join("a/b/file.txt")},
+			{untrusted: "a/b/file.txt/x", expected: root.Join("a/b/file.txt/x")},
+			{untrusted: "../../etc/passwd", err: fs.ErrOutsideRoot},
+			{untrusted: "/../etc/passwd", err: fs.ErrOutsideRoot},
+			{untrusted: "a/../../root/a", err: fs.ErrOutsideRoot},
+			{untrusted: "missing/../../x", err: fs.ErrOutsideRoot},
+			{untrusted: "missing/../link-out/secret", err: fs.ErrOutsideRoot},
+			{untrusted: "link-out/secret", err: fs.ErrOutsideRoot},
+			{untrusted: "link-abs-out/secret", err: fs.ErrOutsideRoot},
+			{untrusted: "a/up/../x", err: fs.ErrOutsideRoot},
+			{untrusted: "loop1", err: syscall.ELOOP},
+		}
+
+		for i, test := range tests {
+			received, err := root.SecureJoin(test.untrusted)
+			if !errors.Is(err, test.err) || received != test.expected {
+				t.Errorf("Case %d, error testing secure join of '%s': expected '%s' (%v), received '%s' (%v)", i, test.untrusted, test.expected, test.err, received, err)
+			}
+
+			var escape *fs.EscapeError
+			if errors.As(err, &escape) && (escape.Root != root || escape.Untrusted != test.untrusted) {
+				t.Errorf("Case %d, error testing escape error: received '%v'", i, escape)
+			}
+		}
+	})
+}
+
+func TestSecureJoinSymlinkRace(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir).Join("root")
+		outside := fs.Path(dir).Join("outside")
+
+		for _, file := range []fs.Path{root.Join("dir/secret"), outside.Join("secret")} {
+			if err := file.Touch(); err != nil {
+				t.Errorf("Error creating file: %v", err)
+				return
+			}
+		}
+
+		realRoot, err := filepath.EvalSymlinks(root.String())
+		if err != nil {
+			t.Errorf("Error resolving root: %v", err)
+			return
+		}
+
+		if err := root.Join("race").Symlink("dir"); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		// the path keeps being replaced, atomically, by a link to a directory
+		// inside the root and by a link leading outside of it
+		go func() {
+			defer wg.Done()
+			race := root.Join("race").String()
+			next := root.Join("next").String()
+
+			for i := 0; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				targets := []string{"dir", "../outside"}
+				_ = os.Remove(next)
+				_ = os.Symlink(targets[i%len(targets)], next)
+				_ = os.Rename(next, race)
+			}
+		}()
+
+		for i := 0; i < 2000; i++ {
+			path, err := root.SecureJoin("race/secret")
+
+			if err != nil && !errors.Is(err, fs.ErrOutsideRoot) {
+				t.Errorf("Case %d, unexpected error on secure join: %v", i, err)
+				break
+			}
+
+			if err != nil {
+				continue
+			}
+
+			// the path returned must lead to a file inside the root, whatever
+			// the links are replaced with afterwards
+			real, err := filepath.EvalSymlinks(path.String())
+			if err != nil || !fs.Path(real).IsChildOf(fs.Path(realRoot)) {
+				t.Errorf("Case %d, path '%s' leads to '%s' outside the root directory (%v)", i, path, real, err)
+				break
+			}
+		}
+
+		close(done)
+		wg.Wait()
+	})
+}
+
+func TestSecureJoinMissingRace(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir).Join("root")
+		outside := fs.Path(dir).Join("outside")
+		race := root.Join("race")
+
+		for _, file := range []fs.Path{root.Join("dir/secret"), outside.Join("secret")} {
+			if err := file.Touch(); err != nil {
+				t.Errorf("Error creating file: %v", err)
+				return
+			}
+		}
+
+		// the missing component is replaced by a link leading outside the
+		// root right after being looked up
+		defer fs.SetLstat(func(name string) (os.FileInfo, error) {
+			if fs.Path(name).IsChildOf(race) {
+				t.Errorf("Error testing secure join: '%s' looked up through a missing component", name)
+			}
+
+			info, err := os.Lstat(name)
+			if fs.Path(name) == race && os.IsNotExist(err) {
+				if err := race.Symlink("../outside"); err != nil {
+					t.Errorf("Error creating link: %v", err)
+				}
+			}
+			return info, err
+		})()
+
+		tests := []struct {
+			untrusted string
+			expected  fs.Path
+			err       error
+		}{
+			{untrusted: "race/secret", expected: race.Join("secret")},
+			{untrusted: "race/secret", err: fs.ErrOutsideRoot},
+		}
+
+		for i, test := range tests {
+			received, err := root.SecureJoin(test.untrusted)
+			if !errors.Is(err, test.err) || received != test.expected {
+				t.Errorf("Case %d, error testing secure join of '%s': expected '%s' (%v), received '%s' (%v)", i, test.untrusted, test.expected, test.err, received, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package fs
+
+import (
+	"crypto/rand"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultShredPasses is the number of random passes done when none is given
+	defaultShredPasses = 3
+
+	// shredBufferSize is the size of the chunks written on each pass
+	shredBufferSize = 64 * 1024
+)
+
+// Shred securely removes a file. Its content is overwritten with random data on
+// each of the given passes, plus a final pass with zeros, each of them synced to
+// disk. The file is then truncated, renamed to a random name and removed.
+// A number of passes lower or equal to zero means the default of 3 passes.
+//
+// Note that journaling and copy-on-write filesystems, as well as SSDs, may keep
+// copies of the data elsewhere, out of reach of the overwriting.
+func (p Path) Shred(passes int) error {
+	info, err := lstat(p.String())
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if info.IsDir() {
+		return newError("shred", p, ErrPathIsDirectory)
+	}
+
+	// the target of a link is not shredded, only the link is removed
+	if info.Mode().IsRegular() {
+		if err := overwrite(p, passes); err != nil {
+			return err
+		}
+	}
+
+	// the random name hides the original one from the directory entry
+	hidden := p.Parent().Join(uuid.New().String())
+	if err := os.Rename(p.String(), hidden.String()); err != nil {
+		return wrapError(err)
+	}
+
+	return wrapError(os.Remove(hidden.String()))
+}
+
+// ShredAll works like Shred, but also accepts directories, shredding every file
+// inside of them before removing them.
+func (p Path) ShredAll(passes int) error {
+	info, err := os.Lstat(p.String())
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if !info.IsDir() {
+		return p.Shred(passes)
+	}
+
+	if p.IsProtected() {
+		return newError("shred", p, ErrProtectedPath)
+	}
+
+	if err := p.WalkDir(WalkOptions{Type: WalkFiles}, func(entry *DirEntry) error {
+		return entry.Path().Shred(passes)
+	}); err != nil {
+		return err
+	}
+
+	return p.RemoveAll()
+}
+
+// overwrite writes the passes over the content of a file and truncates it. The
+// file is opened without following links, and its type and size are taken from
+// the open file, so it can't be replaced by another one in between.
+func overwrite(p Path, passes int) error {
+	if passes <= 0 {
+		passes = defaultShredPasses
+	}
+
+	opts := OpenOptions{NoFollow: true, NoParents: true}
+	file, err := DefaultFilesystem.openFile(p, os.O_WRONLY|nonBlockFlag, 0, opts)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if !info.Mode().IsRegular() {
+		return newError("shred", p, ErrFileDoesNotExist)
+	}
+	size := info.Size()
+
+	buffer := make([]byte, shredBufferSize)
+	for pass := 0; pass <= passes; pass++ {
+		// the last pass writes zeros
+		random := pass < passes
+
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return wrapError(err)
+		}
+
+		for written := int64(0); written < size; {
+			chunk := buffer
+			if remaining := size - written; remaining < int64(len(chunk)) {
+				chunk = chunk[:remaining]
+			}
+
+			if random {
+				if _, err := rand.Read(chunk); err != nil {
+					return newError("shred", p, err)
+				}
+			} else {
+				for i := range chunk {
+					chunk[i] = 0
+				}
+			}
+
+			n, err := file.Write(chunk)
+			if err != nil {
+				return wrapError(err)
+			}
+			written += int64(n)
+		}
+
+		if err := file.Sync(); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	if err := file.Truncate(0); err != nil {
+		return wrapError(err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return wrapError(err)
+	}
+
+	return wrapError(file.Close())
+}
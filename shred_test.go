@@ -0,0 +1,157 @@
+package fs_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestShred(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		secret := root.Join("secret.txt")
+		link := root.Join("link.txt")
+
+		f, err := secret.Create()
+		if err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+		_, _ = f.Write([]byte(strings.Repeat("password", 20000)))
+		f.Close()
+
+		// a hard link keeps the data reachable after the removal
+		if err := os.Link(secret.String(), link.String()); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		if err := secret.Shred(1); err != nil {
+			t.Errorf("Error shredding: %v", err)
+		}
+
+		if secret.Exists() {
+			t.Errorf("Path '%s' should have been removed", secret)
+		}
+
+		if data, err := link.ReadAll(); err != nil || len(data) != 0 {
+			t.Errorf("Error testing shred: the content should have been truncated, received %d bytes (%v)", len(data), err)
+		}
+
+		if entries, _ := root.ReadDir(); len(entries) != 1 {
+			t.Errorf("Error testing shred: unexpected entries left %v", entries)
+		}
+
+		tests := []struct {
+			path     fs.Path
+			expected error
+		}{
+			{path: root.Join("missing"), expected: fs.ErrNotFound},
+			{path: root, expected: fs.ErrPathIsDirectory},
+		}
+
+		for i, test := range tests {
+			if err := test.path.Shred(0); !errors.Is(err, test.expected) {
+				t.Errorf("Case %d, error testing shred: expected '%v', received '%v'", i, test.expected, err)
+			}
+		}
+	})
+}
+
+func TestShredAll(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		tree := root.Join("tree")
+
+		if err := createWalkTree(tree); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		outside := root.Join("outside.txt")
+		f, err := outside.Create()
+		if err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+		_, _ = f.Write([]byte("keep me"))
+		f.Close()
+
+		// links inside the tree are removed without touching their targets
+		if err := os.Symlink(outside.String(), tree.Join("b/link").String()); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		if err := tree.ShredAll(2); err != nil {
+			t.Errorf("Error shredding tree: %v", err)
+		}
+
+		if tree.Exists() {
+			t.Errorf("Path '%s' should have been removed", tree)
+		}
+
+		if data, _ := outside.ReadAll(); string(data) != "keep me" {
+			t.Errorf("Error testing shred all: the target of a link was changed to '%s'", data)
+		}
+
+		// the guard is tested on a path protected inside the temporary
+		// directory, so a failure can't shred anything else
+		protected := root.Join("protected")
+		if err := protected.Join("file.txt").Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+
+		withProtected(protected, func() {
+			if err := protected.ShredAll(1); !errors.Is(err, fs.ErrProtectedPath) {
+				t.Errorf("Error testing shred all: expected '%v', received '%v'", fs.ErrProtectedPath, err)
+			}
+		})
+
+		if !protected.Join("file.txt").Exists() {
+			t.Errorf("Error testing shred all: the protected path should be kept")
+		}
+	})
+}
+
+func TestShredSwappedForLink(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		path := root.Join("target")
+		outside := root.Join("outside.txt")
+
+		for _, file := range []fs.Path{path, outside} {
+			if err := ioutil.WriteFile(file.String(), []byte("keep me"), 0644); err != nil {
+				t.Errorf("Error writing file: %v", err)
+				return
+			}
+		}
+
+		// the file is replaced by a link leading out right after being looked up
+		defer fs.SetLstat(func(name string) (os.FileInfo, error) {
+			info, err := os.Lstat(name)
+			if name == path.String() && err == nil && info.Mode().IsRegular() {
+				if err := os.Remove(name); err != nil {
+					t.Errorf("Error removing file: %v", err)
+				}
+				if err := path.Symlink(outside); err != nil {
+					t.Errorf("Error creating link: %v", err)
+				}
+			}
+			return info, err
+		})()
+
+		if err := path.Shred(1); !errors.Is(err, fs.ErrIsSymlink) {
+			t.Errorf("Error testing shred of a swapped file: expected '%v', received '%v'", fs.ErrIsSymlink, err)
+		}
+
+		if data, err := outside.ReadAll(); err != nil || string(data) != "keep me" {
+			t.Errorf("Error testing shred: the target of a link was changed to '%s' (%v)", data, err)
+		}
+	})
+}
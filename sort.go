@@ -0,0 +1,148 @@
+package fs
+
+import (
+	"os"
+	"sort"
+)
+
+// SortOrder determines the order in which the entries of a directory are visited
+type SortOrder uint
+
+const (
+	// SortNone keeps the order in which the operating system returns the entries
+	SortNone SortOrder = iota
+
+	// SortByName visits the entries in lexical order of their names
+	SortByName
+
+	// SortNatural visits the entries in natural order of their names, where the
+	// sequences of digits are compared by their numeric value, so "file2" comes
+	// before "file10".
+	SortNatural
+
+	// SortBySize visits the entries from the smallest to the largest one, which
+	// requires fetching the information of every entry
+	SortBySize
+
+	// SortByModTime visits the entries from the oldest to the most recently
+	// modified one, which requires fetching the information of every entry
+	SortByModTime
+)
+
+// sortEntries sorts the entries of a directory in the given order. Entries with
+// the same size or modification time are sorted by name. Entries whose information
+// can't be fetched are sorted as empty and never modified.
+func sortEntries(entries []os.DirEntry, order SortOrder) {
+	switch order {
+	case SortByName:
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	case SortNatural:
+		sort.Slice(entries, func(i, j int) bool { return naturalLess(entries[i].Name(), entries[j].Name()) })
+	case SortBySize, SortByModTime:
+		// the information fetched is kept with the entries, to be reused
+		infos := make([]os.FileInfo, len(entries))
+		for i := range entries {
+			if infos[i], _ = entries[i].Info(); infos[i] != nil {
+				entries[i] = &infoEntry{DirEntry: entries[i], info: infos[i]}
+			}
+		}
+
+		sort.Sort(&entriesByInfo{entries: entries, infos: infos, order: order})
+	}
+}
+
+// infoEntry is a entry of a directory whose information was already fetched
+type infoEntry struct {
+	os.DirEntry
+	info os.FileInfo
+}
+
+// Info returns the information fetched for the entry
+func (e *infoEntry) Info() (os.FileInfo, error) {
+	return e.info, nil
+}
+
+// entriesByInfo sorts entries by size or modification time
+type entriesByInfo struct {
+	entries []os.DirEntry
+	infos   []os.FileInfo
+	order   SortOrder
+}
+
+func (s *entriesByInfo) Len() int {
+	return len(s.entries)
+}
+
+func (s *entriesByInfo) Swap(i, j int) {
+	s.entries[i], s.entries[j] = s.entries[j], s.entries[i]
+	s.infos[i], s.infos[j] = s.infos[j], s.infos[i]
+}
+
+func (s *entriesByInfo) Less(i, j int) bool {
+	a, b := s.infos[i], s.infos[j]
+
+	switch {
+	case a == nil || b == nil:
+		if a != b {
+			return a == nil
+		}
+	case s.order == SortBySize && a.Size() != b.Size():
+		return a.Size() < b.Size()
+	case s.order == SortByModTime && !a.ModTime().Equal(b.ModTime()):
+		return a.ModTime().Before(b.ModTime())
+	}
+
+	return s.entries[i].Name() < s.entries[j].Name()
+}
+
+// naturalLess compares two strings in natural order
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, ra := splitDigits(a)
+			nb, rb := splitDigits(b)
+
+			// compare the numbers without their leading zeros, the longer the greater
+			ta, tb := trimZeros(na), trimZeros(nb)
+			if len(ta) != len(tb) {
+				return len(ta) < len(tb)
+			}
+			if ta != tb {
+				return ta < tb
+			}
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+
+			a, b = ra, rb
+			continue
+		}
+
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) < len(b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// splitDigits splits the leading sequence of digits of a string from the rest of it
+func splitDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func trimZeros(s string) string {
+	for len(s) > 1 && s[0] == '0' {
+		s = s[1:]
+	}
+	return s
+}
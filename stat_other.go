@@ -0,0 +1,32 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package fs
+
+import (
+	"os"
+)
+
+// fileID identifies a file by its device and inode
+type fileID struct {
+	device uint64
+	inode  uint64
+}
+
+// deviceOf returns the identifier of the device containing the file, which is
+// not available on this platform.
+func deviceOf(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
+
+// hardLinksOf returns the identifier of the file and its number of hard links,
+// which are not available on this platform.
+func hardLinksOf(info os.FileInfo) (fileID, uint64, bool) {
+	return fileID{}, 0, false
+}
+
+// ownerOf returns the numeric user and group ids of the owner of the file,
+// which are not available on this platform.
+func ownerOf(info os.FileInfo) (uid, gid int, ok bool) {
+	return -1, -1, false
+}
@@ -0,0 +1,39 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package fs
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID identifies a file by its device and inode
+type fileID struct {
+	device uint64
+	inode  uint64
+}
+
+// deviceOf returns the identifier of the device containing the file
+func deviceOf(info os.FileInfo) (uint64, bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(stat.Dev), true
+	}
+	return 0, false
+}
+
+// hardLinksOf returns the identifier of the file and its number of hard links
+func hardLinksOf(info os.FileInfo) (fileID, uint64, bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return fileID{device: uint64(stat.Dev), inode: uint64(stat.Ino)}, uint64(stat.Nlink), true
+	}
+	return fileID{}, 0, false
+}
+
+// ownerOf returns the numeric user and group ids of the owner of the file
+func ownerOf(info os.FileInfo) (uid, gid int, ok bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(stat.Uid), int(stat.Gid), true
+	}
+	return -1, -1, false
+}
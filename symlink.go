@@ -0,0 +1,57 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Symlink creates the path as a symbolic link pointing to the target
+func (p Path) Symlink(target Path) error {
+	return wrapError(os.Symlink(target.String(), p.String()))
+}
+
+// Readlink returns the target of the symbolic link
+func (p Path) Readlink() (Path, error) {
+	target, err := os.Readlink(p.String())
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return Path(target), nil
+}
+
+// EvalSymlinks returns the path after the evaluation of all the symbolic links in it
+func (p Path) EvalSymlinks() (Path, error) {
+	path, err := filepath.EvalSymlinks(p.String())
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return Path(path), nil
+}
+
+// LInfo returns a info of a path, describing the symbolic link itself instead
+// of its target when the path is a link.
+func (p Path) LInfo() os.FileInfo {
+	if info, err := os.Lstat(p.String()); err == nil {
+		return info
+	}
+	return nil
+}
+
+// IsSymlink returns true if the given path exists and is a symbolic link
+func (p Path) IsSymlink() bool {
+	if info := p.LInfo(); info != nil {
+		return info.Mode()&os.ModeSymlink != 0
+	}
+	return false
+}
+
+// IsDangling returns true if the given path is a symbolic link whose target
+// does not exist.
+func (p Path) IsDangling() bool {
+	return p.IsSymlink() && !p.Exists()
+}
+
+// IsSymlink returns true when the entry is a symbolic link
+func (e *DirEntry) IsSymlink() bool {
+	return e.Type()&os.ModeSymlink != 0
+}
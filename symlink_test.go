@@ -0,0 +1,150 @@
+package fs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestSymlink(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		file := root.Join("dir/file.txt")
+
+		f, err := file.Create()
+		if err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+		f.Close()
+
+		links := []struct {
+			path   fs.Path
+			target fs.Path
+		}{
+			{path: root.Join("file-link"), target: file},
+			{path: root.Join("dir-link"), target: "dir"},
+			{path: root.Join("dangling-link"), target: root.Join("missing")},
+		}
+
+		for i, link := range links {
+			if err := link.path.Symlink(link.target); err != nil {
+				t.Errorf("Case %d, error creating link: %v", i, err)
+			}
+		}
+
+		if err := links[0].path.Symlink(file); !errors.Is(err, fs.ErrPathExists) {
+			t.Errorf("Error testing existing link: expected '%v', received '%v'", fs.ErrPathExists, err)
+		}
+
+		tests := []struct {
+			path      fs.Path
+			symlink   bool
+			dangling  bool
+			exists    bool
+			target    fs.Path
+			evaluated fs.Path
+		}{
+			{path: file, exists: true, evaluated: file},
+			{path: links[0].path, symlink: true, exists: true, target: file, evaluated: file},
+			{path: links[1].path, symlink: true, exists: true, target: "dir", evaluated: file.Parent()},
+			{path: links[1].path.Join("file.txt"), exists: true, evaluated: file},
+			{path: links[2].path, symlink: true, dangling: true, target: root.Join("missing")},
+			{path: root.Join("missing")},
+		}
+
+		for i, test := range tests {
+			if received := test.path.IsSymlink(); received != test.symlink {
+				t.Errorf("Case %d, error testing IsSymlink: expected '%v', received '%v'", i, test.symlink, received)
+			}
+
+			if received := test.path.IsDangling(); received != test.dangling {
+				t.Errorf("Case %d, error testing IsDangling: expected '%v', received '%v'", i, test.dangling, received)
+			}
+
+			if received := test.path.LInfo() != nil; received != (test.exists || test.symlink) {
+				t.Errorf("Case %d, error testing LInfo: expected '%v', received '%v'", i, test.exists || test.symlink, received)
+			}
+
+			if received, _ := test.path.Readlink(); received != test.target {
+				t.Errorf("Case %d, error testing Readlink: expected '%v', received '%v'", i, test.target, received)
+			}
+
+			received, err := test.path.EvalSymlinks()
+			if test.exists && err != nil {
+				t.Errorf("Case %d, error evaluating links: %v", i, err)
+			} else if !test.exists && !errors.Is(err, fs.ErrNotFound) {
+				t.Errorf("Case %d, error testing EvalSymlinks: expected '%v', received '%v'", i, fs.ErrNotFound, err)
+			}
+
+			if evaluated, _ := test.evaluated.EvalSymlinks(); test.exists && received != evaluated {
+				t.Errorf("Case %d, error testing EvalSymlinks: expected '%v', received '%v'", i, evaluated, received)
+			}
+		}
+	})
+}
+
+func TestCopyToWithOptionsSymlinks(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		src := root.Join("src")
+
+		if err := createWalkTree(src); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		if err := src.Join("a-link").Symlink("a.txt"); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		if err := src.Join("b-link").Symlink("b"); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		tests := []struct {
+			mode     fs.SymlinkMode
+			links    bool
+			contents bool
+		}{
+			{mode: fs.SymlinkFollow, links: false, contents: true},
+			{mode: fs.SymlinkPreserve, links: true, contents: true},
+			{mode: fs.SymlinkSkip, links: false, contents: false},
+		}
+
+		for i, test := range tests {
+			dest := root.Join("dest").Join(string(rune('a' + i)))
+
+			if err := src.CopyToWithOptions(dest, fs.CopyOptions{Symlinks: test.mode}); err != nil {
+				t.Errorf("Case %d, error copying: %v", i, err)
+				continue
+			}
+
+			for _, link := range []fs.Path{dest.Join("a-link"), dest.Join("b-link")} {
+				if link.IsSymlink() != test.links {
+					t.Errorf("Case %d, error testing copy of link '%s': expected link '%v', received '%v'", i, link, test.links, link.IsSymlink())
+				}
+			}
+
+			if dest.Join("a-link").FileExists() != test.contents || dest.Join("b-link/d/e/f.txt").FileExists() != test.contents {
+				t.Errorf("Case %d, error testing copy of link contents: expected '%v'", i, test.contents)
+			}
+
+			if target, _ := dest.Join("b-link").Readlink(); test.links && target != "b" {
+				t.Errorf("Case %d, error testing copy of link target: expected 'b', received '%s'", i, target)
+			}
+		}
+
+		// a single link is copied as a link too
+		if err := src.Join("a-link").CopyToWithOptions(root.Join("dest"), fs.CopyOptions{Symlinks: fs.SymlinkPreserve}); err != nil {
+			t.Errorf("Error copying a single link: %v", err)
+		}
+
+		if target, _ := root.Join("dest/a-link").Readlink(); target != "a.txt" {
+			t.Errorf("Error testing copy of a single link: expected 'a.txt', received '%s'", target)
+		}
+	})
+}
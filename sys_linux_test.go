@@ -0,0 +1,25 @@
+package fs_test
+
+import (
+	"syscall"
+	"time"
+
+	"github.com/plateausnetwork/fs"
+	"golang.org/x/sys/unix"
+)
+
+// mkfifo creates a named pipe at the path
+func mkfifo(path fs.Path) error {
+	return syscall.Mkfifo(path.String(), 0644)
+}
+
+// exchange atomically swaps the paths
+func exchange(a, b fs.Path) error {
+	return unix.Renameat2(unix.AT_FDCWD, a.String(), unix.AT_FDCWD, b.String(), unix.RENAME_EXCHANGE)
+}
+
+// lchtimes changes the times of the path, without following symbolic links
+func lchtimes(path fs.Path, when time.Time) error {
+	times := []unix.Timespec{unix.NsecToTimespec(when.UnixNano()), unix.NsecToTimespec(when.UnixNano())}
+	return unix.UtimesNanoAt(unix.AT_FDCWD, path.String(), times, unix.AT_SYMLINK_NOFOLLOW)
+}
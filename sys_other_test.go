@@ -0,0 +1,26 @@
+//go:build !linux
+// +build !linux
+
+package fs_test
+
+import (
+	"time"
+
+	"github.com/plateausnetwork/fs"
+)
+
+// mkfifo creates a named pipe at the path, which is not supported on this platform
+func mkfifo(path fs.Path) error {
+	return fs.ErrNotSupported
+}
+
+// exchange atomically swaps the paths, which is not supported on this platform
+func exchange(a, b fs.Path) error {
+	return fs.ErrNotSupported
+}
+
+// lchtimes changes the times of the path, without following symbolic links,
+// which is not supported on this platform
+func lchtimes(path fs.Path, when time.Time) error {
+	return fs.ErrNotSupported
+}
@@ -0,0 +1,36 @@
+package fs
+
+import (
+	"os"
+	"time"
+)
+
+// Touch creates the file when it does not exist, or sets its access and
+// modification times to the current time otherwise.
+func (p Path) Touch() error {
+	if _, err := os.Stat(p.String()); err == nil {
+		now := time.Now()
+		return p.Chtimes(now, now)
+	}
+
+	file, err := open(p, os.O_WRONLY|os.O_CREATE, DefaultFilesystem.fileMode())
+	if err != nil {
+		return err
+	}
+
+	return wrapError(file.Close())
+}
+
+// Chtimes changes the access and modification times of the path
+func (p Path) Chtimes(atime, mtime time.Time) error {
+	return wrapError(os.Chtimes(p.String(), atime, mtime))
+}
+
+// MTime returns the modification time of the path
+func (p Path) MTime() (time.Time, error) {
+	info, err := os.Stat(p.String())
+	if err != nil {
+		return time.Time{}, wrapError(err)
+	}
+	return info.ModTime(), nil
+}
@@ -0,0 +1,21 @@
+//go:build dragonfly || openbsd
+// +build dragonfly openbsd
+
+package fs
+
+import (
+	"syscall"
+	"time"
+)
+
+// BirthTime returns the creation time of the path, which is not available on
+// this platform.
+func (p Path) BirthTime() (time.Time, error) {
+	return time.Time{}, newError("stat", p, ErrNotSupported)
+}
+
+// statTimes returns the last access time and the last time the metadata
+// changed, as reported by the system
+func statTimes(stat *syscall.Stat_t) (atime, ctime time.Time) {
+	return time.Unix(stat.Atim.Unix()), time.Unix(stat.Ctim.Unix())
+}
@@ -0,0 +1,24 @@
+//go:build darwin || freebsd || netbsd
+// +build darwin freebsd netbsd
+
+package fs
+
+import (
+	"syscall"
+	"time"
+)
+
+// BirthTime returns the creation time of the path
+func (p Path) BirthTime() (time.Time, error) {
+	stat, err := statOf(p)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(stat.Birthtimespec.Unix()), nil
+}
+
+// statTimes returns the last access time and the last time the metadata
+// changed, as reported by the system
+func statTimes(stat *syscall.Stat_t) (atime, ctime time.Time) {
+	return time.Unix(stat.Atimespec.Unix()), time.Unix(stat.Ctimespec.Unix())
+}
@@ -0,0 +1,32 @@
+package fs
+
+import (
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// BirthTime returns the creation time of the path, which is only available
+// on kernels and filesystems supporting statx.
+func (p Path) BirthTime() (time.Time, error) {
+	var stat unix.Statx_t
+	if err := unix.Statx(unix.AT_FDCWD, p.String(), 0, unix.STATX_BTIME, &stat); err != nil {
+		if err == unix.ENOSYS {
+			err = ErrNotSupported
+		}
+		return time.Time{}, newError("statx", p, err)
+	}
+
+	if stat.Mask&unix.STATX_BTIME == 0 {
+		return time.Time{}, newError("statx", p, ErrNotSupported)
+	}
+
+	return time.Unix(stat.Btime.Sec, int64(stat.Btime.Nsec)), nil
+}
+
+// statTimes returns the last access time and the last time the metadata
+// changed, as reported by the system
+func statTimes(stat *syscall.Stat_t) (atime, ctime time.Time) {
+	return time.Unix(stat.Atim.Unix()), time.Unix(stat.Ctim.Unix())
+}
@@ -0,0 +1,40 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package fs
+
+import (
+	"os"
+	"time"
+)
+
+// ATime returns the last access time of the path, which is not available on
+// this platform.
+func (p Path) ATime() (time.Time, error) {
+	return time.Time{}, newError("stat", p, ErrNotSupported)
+}
+
+// CTime returns the last time the metadata of the path changed, which is not
+// available on this platform.
+func (p Path) CTime() (time.Time, error) {
+	return time.Time{}, newError("stat", p, ErrNotSupported)
+}
+
+// BirthTime returns the creation time of the path, which is not available on
+// this platform.
+func (p Path) BirthTime() (time.Time, error) {
+	return time.Time{}, newError("statx", p, ErrNotSupported)
+}
+
+// lchtimes changes the access and modification times of the path, without
+// following it when it is a symbolic link, which is not available on this
+// platform, so the times are left unchanged.
+func lchtimes(p Path, atime, mtime time.Time) error {
+	return nil
+}
+
+// accessTimeOf returns the last access time of the file, which is not
+// available on this platform.
+func accessTimeOf(info os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
@@ -0,0 +1,203 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestTouch(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		file := root.Join("a/b/file.txt")
+
+		if err := file.Touch(); err != nil {
+			t.Errorf("Error touching missing file: %v", err)
+			return
+		}
+
+		if !file.FileExists() {
+			t.Errorf("File '%s' should have been created", file)
+		}
+
+		old := time.Unix(1000, 0)
+		if err := file.Chtimes(old, old); err != nil {
+			t.Errorf("Error changing times: %v", err)
+		}
+
+		tests := []fs.Path{file, root.Join("a")}
+
+		for i, path := range tests {
+			if err := path.Chtimes(old, old); err != nil {
+				t.Errorf("Case %d, error changing times: %v", i, err)
+				continue
+			}
+
+			before := time.Now().Add(-time.Minute)
+			if err := path.Touch(); err != nil {
+				t.Errorf("Case %d, error touching: %v", i, err)
+				continue
+			}
+
+			if mtime, err := path.MTime(); err != nil || mtime.Before(before) {
+				t.Errorf("Case %d, error testing touch: expected a time after '%v', received '%v' (%v)", i, before, mtime, err)
+			}
+		}
+
+		if err := root.Join("missing").Chtimes(old, old); !errors.Is(err, fs.ErrNotFound) {
+			t.Errorf("Error testing chtimes on a missing path: expected '%v', received '%v'", fs.ErrNotFound, err)
+		}
+	})
+}
+
+func TestTimes(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		file := root.Join("file.txt")
+
+		if err := file.Touch(); err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+
+		atime, mtime := time.Unix(1000, 500), time.Unix(2000, 0)
+		if err := file.Chtimes(atime, mtime); err != nil {
+			t.Errorf("Error changing times: %v", err)
+			return
+		}
+
+		if received, err := file.ATime(); err != nil || !received.Equal(atime) {
+			t.Errorf("Error testing ATime: expected '%v', received '%v' (%v)", atime, received, err)
+		}
+
+		if received, err := file.MTime(); err != nil || !received.Equal(mtime) {
+			t.Errorf("Error testing MTime: expected '%v', received '%v' (%v)", mtime, received, err)
+		}
+
+		// the change of the times is itself a change of the metadata
+		before := time.Now().Add(-time.Minute)
+		if received, err := file.CTime(); err != nil || received.Before(before) {
+			t.Errorf("Error testing CTime: expected a time after '%v', received '%v' (%v)", before, received, err)
+		}
+
+		if received, err := file.BirthTime(); err == nil && received.Before(before) {
+			t.Errorf("Error testing BirthTime: expected a time after '%v', received '%v'", before, received)
+		} else if err != nil && !errors.Is(err, fs.ErrNotSupported) {
+			t.Errorf("Error testing BirthTime: %v", err)
+		}
+
+		missing := root.Join("missing")
+		tests := []error{
+			errorOf(missing.ATime()),
+			errorOf(missing.MTime()),
+			errorOf(missing.CTime()),
+			errorOf(missing.BirthTime()),
+		}
+
+		for i, err := range tests {
+			if !errors.Is(err, fs.ErrNotFound) {
+				t.Errorf("Case %d, error testing times of a missing path: expected '%v', received '%v'", i, fs.ErrNotFound, err)
+			}
+		}
+	})
+}
+
+func TestCopyToWithOptionsTimes(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		src := root.Join("src")
+
+		if err := createWalkTree(src); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		paths := []string{"a.txt", "b/d/e/f.txt", "b/d/e", "b/d", "b", ""}
+		for i, path := range paths {
+			when := time.Unix(int64(1000*(i+1)), 0)
+			if err := src.Join(path).Chtimes(when, when); err != nil {
+				t.Errorf("Error changing times: %v", err)
+				return
+			}
+		}
+
+		tests := []bool{false, true}
+
+		for i, preserve := range tests {
+			dest := root.Join("dest").Join(string(rune('a' + i)))
+
+			if err := src.CopyToWithOptions(dest, fs.CopyOptions{PreserveTimes: preserve}); err != nil {
+				t.Errorf("Case %d, error copying: %v", i, err)
+				continue
+			}
+
+			for j, path := range paths {
+				expected := time.Unix(int64(1000*(j+1)), 0)
+				received, err := dest.Join(path).MTime()
+
+				if preserve && (err != nil || !received.Equal(expected)) {
+					t.Errorf("Case %d, error testing preserved time of '%s': expected '%v', received '%v' (%v)", i, path, expected, received, err)
+				}
+
+				if !preserve && (err != nil || received.Equal(expected)) {
+					t.Errorf("Case %d, time of '%s' should not be preserved: received '%v' (%v)", i, path, received, err)
+				}
+			}
+		}
+	})
+}
+
+func TestCopyToWithOptionsSymlinkTimes(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		link := root.Join("src/link")
+
+		if err := link.Parent().MkdirAll(); err != nil {
+			t.Errorf("Error creating directory: %v", err)
+			return
+		}
+
+		if err := link.Symlink("missing"); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		when := time.Unix(1000, 0)
+		if err := lchtimes(link, when); errors.Is(err, fs.ErrNotSupported) {
+			t.Skip("changing the times of links is not supported")
+		} else if err != nil {
+			t.Errorf("Error changing times: %v", err)
+			return
+		}
+
+		tests := []fs.Path{root.Join("src"), link}
+
+		for i, src := range tests {
+			dest := root.Join("dest").Join(string(rune('a' + i)))
+			opts := fs.CopyOptions{Symlinks: fs.SymlinkPreserve, PreserveTimes: true}
+
+			if err := src.CopyToWithOptions(dest, opts); err != nil {
+				t.Errorf("Case %d, error copying: %v", i, err)
+				continue
+			}
+
+			copied := dest
+			if src != link {
+				copied = dest.Join("link")
+			}
+
+			info, err := os.Lstat(copied.String())
+			if err != nil || info.Mode()&os.ModeSymlink == 0 {
+				t.Errorf("Case %d, '%s' should be a link (%v)", i, copied, err)
+				continue
+			}
+
+			if received := info.ModTime(); !received.Equal(when) {
+				t.Errorf("Case %d, error testing preserved time of the link: expected '%v', received '%v'", i, when, received)
+			}
+		}
+	})
+}
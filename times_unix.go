@@ -0,0 +1,69 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ATime returns the last access time of the path
+func (p Path) ATime() (time.Time, error) {
+	stat, err := statOf(p)
+	if err != nil {
+		return time.Time{}, err
+	}
+	atime, _ := statTimes(stat)
+	return atime, nil
+}
+
+// CTime returns the last time the metadata of the path changed
+func (p Path) CTime() (time.Time, error) {
+	stat, err := statOf(p)
+	if err != nil {
+		return time.Time{}, err
+	}
+	_, ctime := statTimes(stat)
+	return ctime, nil
+}
+
+// statOf returns the status of the path as reported by the system
+func statOf(p Path) (*syscall.Stat_t, error) {
+	info, err := os.Stat(p.String())
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, newError("stat", p, ErrNotSupported)
+	}
+	return stat, nil
+}
+
+// lchtimes changes the access and modification times of the path, without
+// following it when it is a symbolic link
+func lchtimes(p Path, atime, mtime time.Time) error {
+	times := []unix.Timespec{
+		unix.NsecToTimespec(atime.UnixNano()),
+		unix.NsecToTimespec(mtime.UnixNano()),
+	}
+
+	if err := unix.UtimesNanoAt(unix.AT_FDCWD, p.String(), times, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return newError("lchtimes", p, err)
+	}
+	return nil
+}
+
+// accessTimeOf returns the last access time of the file, when available
+func accessTimeOf(info os.FileInfo) (time.Time, bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		atime, _ := statTimes(stat)
+		return atime, true
+	}
+	return time.Time{}, false
+}
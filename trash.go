@@ -0,0 +1,393 @@
+package fs
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	trashInfoExt        = ".trashinfo"
+	trashInfoHeader     = "[Trash Info]"
+	trashInfoDateLayout = "2006-01-02T15:04:05"
+)
+
+// TrashItem is an entry of a trash directory, as described by the
+// freedesktop.org Trash specification.
+type TrashItem struct {
+	// Name is the name of the entry in the trash directory
+	Name string
+
+	// OriginalPath is the path the entry was removed from
+	OriginalPath Path
+
+	// DeletionDate is the time the entry was moved to the trash
+	DeletionDate time.Time
+
+	// trash is the trash directory containing the entry
+	trash Path
+}
+
+// Path returns the current path of the entry, inside the trash directory
+func (t TrashItem) Path() Path {
+	return t.trash.Join("files").Join(t.Name)
+}
+
+// Restore moves the entry back to its original path, which must not exist
+func (t TrashItem) Restore() error {
+	if _, err := os.Lstat(t.OriginalPath.String()); err == nil {
+		return newError("restore", t.OriginalPath, ErrPathExists)
+	}
+
+	if err := t.OriginalPath.Parent().MkdirAll(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(t.Path().String(), t.OriginalPath.String()); err != nil {
+		return wrapError(err)
+	}
+
+	return wrapError(os.Remove(t.infoPath().String()))
+}
+
+// Remove removes the entry from the trash permanently
+func (t TrashItem) Remove() error {
+	if err := t.Path().RemoveAll(); err != nil {
+		return err
+	}
+
+	return wrapError(os.Remove(t.infoPath().String()))
+}
+
+// infoPath returns the path of the file holding the metadata of the entry
+func (t TrashItem) infoPath() Path {
+	return t.trash.Join("info").Join(t.Name + trashInfoExt)
+}
+
+// Trash moves the file or directory to the trash. Paths in the same device as
+// the home directory go to the home trash, while the others go to the trash
+// directory at the top of their own device.
+func (p Path) Trash() error {
+	abs := p.Abs().Clean()
+
+	info, err := os.Lstat(abs.String())
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if isProtected(abs.String()) {
+		return newError("trash", p, ErrProtectedPath)
+	}
+
+	trash, topdir, err := trashFor(abs, info)
+	if err != nil {
+		return err
+	}
+
+	// paths are recorded relative to the top directory on other devices,
+	// so they still work when the device is mounted somewhere else
+	original := abs.String()
+	if topdir != "" {
+		if original, err = filepath.Rel(topdir.String(), original); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	name, err := writeTrashInfo(trash, abs.Basename(), original, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(abs.String(), trash.Join("files").Join(name).String()); err != nil {
+		os.Remove(trash.Join("info").Join(name + trashInfoExt).String()) // nolint: errcheck
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+// ListTrash returns the entries of the home trash and of the trash directories
+// of every mounted device.
+func ListTrash() ([]TrashItem, error) {
+	var items []TrashItem
+
+	for _, trash := range trashDirs() {
+		entries, err := trash.dir.Join("info").ReadDirEntries(ReadDirOptions{Sort: SortByName})
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), trashInfoExt) {
+				continue
+			}
+
+			item, err := readTrashInfo(trash.dir, trash.topdir, entry.Path())
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// EmptyTrash permanently removes every entry of the trash directories
+func EmptyTrash() error {
+	items, err := ListTrash()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := item.Remove(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// trashDir is a trash directory and the top directory of the device it
+// belongs to, which is empty for the home trash.
+type trashDir struct {
+	dir    Path
+	topdir Path
+}
+
+// homeTrash returns the trash directory of the user
+func homeTrash() (Path, error) {
+	data, err := xdgHome("XDG_DATA_HOME", ".local/share")
+	if err != nil {
+		return "", err
+	}
+	return data.Join("Trash"), nil
+}
+
+// trashFor returns the trash directory to move the path to, creating it when
+// needed, and the top directory of the device when it is not the home trash.
+func trashFor(p Path, info os.FileInfo) (trash, topdir Path, err error) {
+	home, err := homeTrash()
+	if err != nil {
+		return "", "", wrapError(err)
+	}
+
+	if err := makeTrashDir(home); err != nil {
+		return "", "", err
+	}
+
+	device, ok := deviceOf(info)
+	if !ok {
+		return home, "", nil
+	}
+
+	if homeDevice, ok := deviceOf(home.Info()); !ok || homeDevice == device {
+		return home, "", nil
+	}
+
+	topdir = mountPoint(p.Parent(), device)
+
+	trash = topdirTrash(topdir)
+	if err := makeTrashDir(trash); err != nil {
+		return "", "", err
+	}
+
+	return trash, topdir, nil
+}
+
+// makeTrashDir creates the directories of a trash, only accessible by the user
+func makeTrashDir(trash Path) error {
+	for _, dir := range []string{"files", "info"} {
+		if err := os.MkdirAll(trash.Join(dir).String(), 0700); err != nil {
+			return wrapError(err)
+		}
+	}
+	return nil
+}
+
+// mountPoint returns the top directory of the device containing the path
+func mountPoint(p Path, device uint64) Path {
+	for {
+		parent := p.Parent()
+		if parent == p {
+			return p
+		}
+
+		info, err := os.Stat(parent.String())
+		if err != nil {
+			return p
+		}
+
+		if dev, ok := deviceOf(info); !ok || dev != device {
+			return p
+		}
+
+		p = parent
+	}
+}
+
+// topdirTrash returns the trash directory of the user on a device. The shared
+// $topdir/.Trash directory is used when it was set up by the administrator,
+// i.e. it is a directory, not a link, and has the sticky bit set.
+func topdirTrash(topdir Path) Path {
+	uid := strconv.Itoa(os.Getuid())
+
+	shared := topdir.Join(".Trash")
+	if info, err := os.Lstat(shared.String()); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		return shared.Join(uid)
+	}
+
+	return topdir.Join(".Trash-" + uid)
+}
+
+// trashDirs returns the home trash and the trash directories of the mounted devices
+func trashDirs() []trashDir {
+	var dirs []trashDir
+
+	if home, err := homeTrash(); err == nil {
+		dirs = append(dirs, trashDir{dir: home})
+	}
+
+	for _, topdir := range mountPoints() {
+		trash := topdirTrash(topdir)
+		if trash.DirExists() {
+			dirs = append(dirs, trashDir{dir: trash, topdir: topdir})
+		}
+	}
+
+	return dirs
+}
+
+// mountsFile is the file listing the mount points, in the format of fstab
+var mountsFile = "/proc/self/mounts"
+
+// mountPoints returns the mount points listed by the kernel, when available
+func mountPoints() []Path {
+	file, err := os.Open(mountsFile)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var points []Path
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		// spaces and other special characters are escaped as octal numbers
+		point, err := strconv.Unquote(`"` + strings.ReplaceAll(fields[1], `"`, `\"`) + `"`)
+		if err != nil {
+			continue
+		}
+		points = append(points, Path(point))
+	}
+
+	return points
+}
+
+// writeTrashInfo creates the metadata file of an entry being moved to the
+// trash, choosing a name not used by any other entry.
+func writeTrashInfo(trash Path, base, original string, date time.Time) (string, error) {
+	content := fmt.Sprintf("%s\nPath=%s\nDeletionDate=%s\n",
+		trashInfoHeader,
+		(&url.URL{Path: original}).EscapedPath(),
+		date.Format(trashInfoDateLayout),
+	)
+
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	if stem == "" {
+		stem, ext = base, ""
+	}
+
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s.%d%s", stem, i, ext)
+		}
+
+		info := trash.Join("info").Join(name + trashInfoExt)
+		file, err := os.OpenFile(info.String(), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", wrapError(err)
+		}
+
+		_, err = file.WriteString(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			os.Remove(info.String()) // nolint: errcheck
+			return "", wrapError(err)
+		}
+
+		// the name may also be taken by a file whose metadata was lost
+		if _, err := os.Lstat(trash.Join("files").Join(name).String()); err == nil {
+			os.Remove(info.String()) // nolint: errcheck
+			continue
+		}
+
+		return name, nil
+	}
+}
+
+// readTrashInfo reads the metadata file of an entry of the trash
+func readTrashInfo(trash, topdir, info Path) (TrashItem, error) {
+	item := TrashItem{
+		Name:  strings.TrimSuffix(info.Basename(), trashInfoExt),
+		trash: trash,
+	}
+
+	data, err := info.ReadAll()
+	if err != nil {
+		return item, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := cutString(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Path":
+			original, err := url.PathUnescape(value)
+			if err != nil {
+				return item, newError("trash", info, err)
+			}
+
+			item.OriginalPath = Path(original)
+			if !filepath.IsAbs(original) && topdir != "" {
+				item.OriginalPath = topdir.Join(original)
+			}
+		case "DeletionDate":
+			item.DeletionDate, _ = time.ParseInLocation(trashInfoDateLayout, value, time.Local)
+		}
+	}
+
+	return item, nil
+}
+
+// cutString slices s around the first instance of sep
+func cutString(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
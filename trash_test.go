@@ -0,0 +1,182 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plateausnetwork/fs"
+)
+
+// withTrash runs the handler with the home trash inside the temporary directory,
+// and no other mount point, so the trash directories of the system are left untouched.
+func withTrash(t *testing.T, handler func(root, trash fs.Path)) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		mounts, err := root.Join("mounts").Create()
+		if err != nil {
+			t.Errorf("Error creating the mount points list: %v", err)
+			return
+		}
+		mounts.Close()
+		defer fs.SetMountsFile(mounts.Name())()
+
+		previous, ok := os.LookupEnv("XDG_DATA_HOME")
+		os.Setenv("XDG_DATA_HOME", root.Join("data").String())
+		defer func() {
+			if ok {
+				os.Setenv("XDG_DATA_HOME", previous)
+			} else {
+				os.Unsetenv("XDG_DATA_HOME")
+			}
+		}()
+
+		handler(root, root.Join("data/Trash"))
+	})
+}
+
+func TestTrash(t *testing.T) {
+	withTrash(t, func(root, trash fs.Path) {
+		paths := []fs.Path{
+			root.Join("docs/my file.txt"),
+			root.Join("other/my file.txt"),
+			root.Join("docs/.hidden"),
+		}
+
+		for _, path := range paths {
+			f, err := path.Create()
+			if err != nil {
+				t.Errorf("Error creating file: %v", err)
+				return
+			}
+			_, _ = f.Write([]byte(path.String()))
+			f.Close()
+		}
+
+		before := time.Now().Add(-time.Second)
+		for i, path := range paths {
+			if err := path.Trash(); err != nil {
+				t.Errorf("Case %d, error moving to trash: %v", i, err)
+			}
+
+			if path.Exists() {
+				t.Errorf("Case %d, path '%s' should not exist anymore", i, path)
+			}
+		}
+
+		info, err := trash.Join("info/my file.txt.trashinfo").ReadAll()
+		if err != nil {
+			t.Errorf("Error reading trash info: %v", err)
+		}
+
+		expected := "[Trash Info]\nPath=" + root.Join("docs/my%20file.txt").String() + "\nDeletionDate="
+		if !strings.HasPrefix(string(info), expected) {
+			t.Errorf("Error testing trash info: expected prefix '%s', received '%s'", expected, info)
+		}
+
+		items, err := fs.ListTrash()
+		if err != nil {
+			t.Errorf("Error listing trash: %v", err)
+		}
+
+		names := map[string]fs.Path{
+			".hidden":       paths[2],
+			"my file.txt":   paths[0],
+			"my file.2.txt": paths[1],
+		}
+
+		if len(items) != len(names) {
+			t.Errorf("Error listing trash: expected %d items, received %d", len(names), len(items))
+		}
+
+		for _, item := range items {
+			if original, ok := names[item.Name]; !ok || item.OriginalPath != original {
+				t.Errorf("Unexpected trash item '%s' from '%s'", item.Name, item.OriginalPath)
+			}
+
+			if item.DeletionDate.Before(before) || item.DeletionDate.After(time.Now()) {
+				t.Errorf("Unexpected deletion date for '%s': %v", item.Name, item.DeletionDate)
+			}
+
+			if content, _ := item.Path().ReadAll(); string(content) != item.OriginalPath.String() {
+				t.Errorf("Unexpected content for '%s': '%s'", item.Name, content)
+			}
+		}
+
+		for _, item := range items {
+			if item.Name != "my file.2.txt" {
+				continue
+			}
+
+			if err := item.Restore(); err != nil {
+				t.Errorf("Error restoring: %v", err)
+			}
+
+			if !paths[1].FileExists() {
+				t.Errorf("Path '%s' should have been restored", paths[1])
+			}
+		}
+
+		if err := paths[1].Trash(); err != nil {
+			t.Errorf("Error moving to trash again: %v", err)
+		}
+
+		if err := fs.EmptyTrash(); err != nil {
+			t.Errorf("Error emptying trash: %v", err)
+		}
+
+		if items, _ := fs.ListTrash(); len(items) > 0 {
+			t.Errorf("Trash should be empty, received %v", items)
+		}
+
+		if count := trash.Count(fs.WalkBoth); count != 2 {
+			t.Errorf("Only the trash directories should remain, received %d entries", count)
+		}
+	})
+}
+
+func TestTrashErrors(t *testing.T) {
+	withTrash(t, func(root, trash fs.Path) {
+		f, err := root.Join("a.txt").Create()
+		if err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+		f.Close()
+
+		if err := root.Join("a.txt").Trash(); err != nil {
+			t.Errorf("Error moving to trash: %v", err)
+		}
+
+		f, err = root.Join("a.txt").Create()
+		if err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+		f.Close()
+
+		items, err := fs.ListTrash()
+		if err != nil || len(items) != 1 {
+			t.Errorf("Error listing trash: %v %v", items, err)
+			return
+		}
+
+		tests := []struct {
+			err      error
+			expected error
+		}{
+			{err: root.Join("missing").Trash(), expected: fs.ErrNotFound},
+			{err: fs.Path("/").Trash(), expected: fs.ErrProtectedPath},
+			{err: items[0].Restore(), expected: fs.ErrPathExists},
+		}
+
+		for i, test := range tests {
+			if !errors.Is(test.err, test.expected) {
+				t.Errorf("Case %d, error testing trash errors: expected '%v', received '%v'", i, test.expected, test.err)
+			}
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package fs_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func TestCreateUnique(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := root.Join("docs/taken.d").MkdirAll(); err != nil {
+			t.Errorf("Error creating directory: %v", err)
+			return
+		}
+
+		tests := []struct {
+			path     fs.Path
+			pattern  string
+			expected fs.Path
+		}{
+			{path: root.Join("docs/report.pdf"), expected: root.Join("docs/report.pdf")},
+			{path: root.Join("docs/report.pdf"), expected: root.Join("docs/report (1).pdf")},
+			{path: root.Join("docs/report.pdf"), expected: root.Join("docs/report (2).pdf")},
+			{path: root.Join("docs/report.pdf"), pattern: "-%03d", expected: root.Join("docs/report-001.pdf")},
+			{path: root.Join("docs/archive.tar.gz"), expected: root.Join("docs/archive.tar.gz")},
+			{path: root.Join("docs/archive.tar.gz"), expected: root.Join("docs/archive (1).tar.gz")},
+			{path: root.Join("docs/README"), expected: root.Join("docs/README")},
+			{path: root.Join("docs/README"), pattern: "_%d", expected: root.Join("docs/README_1")},
+			{path: root.Join("docs/taken.d"), expected: root.Join("docs/taken (1).d")},
+			{path: root.Join("new/dir/file.txt"), expected: root.Join("new/dir/file.txt")},
+		}
+
+		for i, test := range tests {
+			file, path, err := test.path.CreateUnique(test.pattern)
+			if err != nil || path != test.expected {
+				t.Errorf("Case %d, error testing create unique: expected '%s', received '%s' (%v)", i, test.expected, path, err)
+				continue
+			}
+
+			if _, err := file.Write([]byte(path)); err != nil {
+				t.Errorf("Case %d, error writing file: %v", i, err)
+			}
+			file.Close()
+		}
+
+		if data, _ := root.Join("docs/report.pdf").ReadAll(); string(data) != root.Join("docs/report.pdf").String() {
+			t.Errorf("Existing files should not be overridden, received '%s'", data)
+		}
+
+		patterns := []string{"-", " (%d)/x", "%s"}
+		for i, pattern := range patterns {
+			if _, _, err := root.Join("docs/report.pdf").CreateUnique(pattern); !errors.Is(err, fs.ErrInvalidName) {
+				t.Errorf("Case %d, error testing invalid pattern: expected '%v', received '%v'", i, fs.ErrInvalidName, err)
+			}
+		}
+	})
+}
+
+func TestCreateUniqueConcurrent(t *testing.T) {
+	WithTempDir(func(dir string) {
+		path := fs.Path(dir).Join("upload.bin")
+
+		const workers = 32
+		paths := make(chan fs.Path, workers)
+
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				file, path, err := path.CreateUnique("")
+				if err != nil {
+					t.Errorf("Error creating unique file: %v", err)
+					return
+				}
+				file.Close()
+				paths <- path
+			}()
+		}
+
+		wg.Wait()
+		close(paths)
+
+		chosen := make(map[fs.Path]bool)
+		for path := range paths {
+			if chosen[path] {
+				t.Errorf("Path '%s' was chosen more than once", path)
+			}
+			chosen[path] = true
+		}
+
+		if len(chosen) != workers {
+			t.Errorf("Error testing concurrent create unique: expected %d paths, received %d", workers, len(chosen))
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package fs
+
+import (
+	"strings"
+)
+
+// ValidationProfile describes the limits a path is checked against by Validate
+type ValidationProfile struct {
+	// MaxNameLength is the length in bytes of the longest component allowed
+	MaxNameLength int
+
+	// MaxPathLength is the length in bytes of the longest path allowed
+	MaxPathLength int
+
+	// PortableNames restricts the components to the POSIX portable filename
+	// character set, letters, digits, ".", "_" and "-", not starting with "-".
+	PortableNames bool
+}
+
+var (
+	// PortableProfile are the limits every POSIX system supports, as given by
+	// _POSIX_NAME_MAX and _POSIX_PATH_MAX, with portable names.
+	PortableProfile = ValidationProfile{MaxNameLength: 14, MaxPathLength: 255, PortableNames: true}
+
+	// LinuxProfile are the limits of Linux, as given by NAME_MAX and PATH_MAX
+	LinuxProfile = ValidationProfile{MaxNameLength: 255, MaxPathLength: 4095}
+)
+
+// Validate checks that the path is within the limits of the profile, returning
+// a error describing the first problem found.
+func (p Path) Validate(profile ValidationProfile) error {
+	if p.Empty() {
+		return newError("validate", p, ErrPathIsEmpty)
+	}
+
+	if strings.IndexByte(p.String(), 0) >= 0 {
+		return newError("validate", p, ErrInvalidName)
+	}
+
+	if profile.MaxPathLength > 0 && len(p) > profile.MaxPathLength {
+		return newError("validate", p, ErrPathTooLong)
+	}
+
+	for _, name := range splitComponents(p.String()) {
+		if profile.MaxNameLength > 0 && len(name) > profile.MaxNameLength {
+			return newError("validate", p, ErrNameTooLong)
+		}
+
+		if profile.PortableNames && !isPortableName(name) {
+			return newError("validate", p, ErrInvalidName)
+		}
+	}
+
+	return nil
+}
+
+// isPortableName returns true when the name only has characters of the POSIX
+// portable filename character set, and doesn't start with "-".
+func isPortableName(name string) bool {
+	if strings.HasPrefix(name, "-") {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '.' && c != '_' && c != '-' && !isDigit(c) && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,285 @@
+package fs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WalkFilter reports whether a given entry should be passed to the walker function.
+// Filters never prevent the walking routine from descending into a directory.
+type WalkFilter func(path Path, info os.FileInfo) bool
+
+// WalkOptions configures the walking routine done by WalkWithOptions and WalkDir
+type WalkOptions struct {
+	// Type determines which kind of entries are passed to the walker function
+	Type WalkType
+
+	// MinDepth is the minimum depth of an entry to be passed to the walker function.
+	// The direct children of the walked path have depth 1.
+	MinDepth int
+
+	// MaxDepth is the maximum depth to descend to. Zero means no limit, so a
+	// MaxDepth of 1 only visits the direct children of the walked path.
+	MaxDepth int
+
+	// Sort determines the order in which the entries of each directory are visited
+	Sort SortOrder
+
+	// FollowSymlinks makes the walking routine descend into symbolic links pointing
+	// to directories. Links leading back to a directory being walked are not followed.
+	FollowSymlinks bool
+
+	// Filters are the predicates an entry must satisfy to be passed to the walker function
+	Filters []WalkFilter
+}
+
+// WalkWithOptions walks recursively on every item under the path and calls the
+// walker function for the ones selected by the options. The walker function may
+// return filepath.SkipDir to skip a directory, or ErrStopWalk to stop the walking
+// routine without an error.
+func (p Path) WalkWithOptions(opts WalkOptions, walker func(path Path, isDirectory bool) error) error {
+	return p.WalkDir(opts, func(entry *DirEntry) error {
+		return walker(entry.Path(), entry.IsDir())
+	})
+}
+
+// WalkDir works like WalkWithOptions, but passes the entries to the walker function.
+// The directories are read without fetching the information of every entry, which is
+// only done when the walker function asks for it, or when required by the options.
+func (p Path) WalkDir(opts WalkOptions, walker func(entry *DirEntry) error) error {
+	info, err := statDir("walk", p)
+	if err != nil {
+		return err
+	}
+
+	w := &walkState{
+		opts:      opts,
+		walker:    walker,
+		ancestors: []os.FileInfo{info},
+	}
+
+	if err := w.walk(p, 1); err != nil && err != ErrStopWalk {
+		return err
+	}
+
+	return nil
+}
+
+// NameFilter selects the entries whose name matches the given shell pattern,
+// as described by filepath.Match.
+func NameFilter(pattern string) WalkFilter {
+	return func(path Path, info os.FileInfo) bool {
+		matched, err := filepath.Match(pattern, info.Name())
+		return err == nil && matched
+	}
+}
+
+// SizeFilter selects the files with a size between min and max bytes, inclusive.
+// A max lower or equal to zero means no upper limit. Directories never match.
+func SizeFilter(min, max int64) WalkFilter {
+	return func(path Path, info os.FileInfo) bool {
+		if info.IsDir() {
+			return false
+		}
+		return info.Size() >= min && (max <= 0 || info.Size() <= max)
+	}
+}
+
+// ModTimeFilter selects the entries modified between after and before, inclusive.
+// A zero time means no limit on that side.
+func ModTimeFilter(after, before time.Time) WalkFilter {
+	return func(path Path, info os.FileInfo) bool {
+		mtime := info.ModTime()
+		if !after.IsZero() && mtime.Before(after) {
+			return false
+		}
+		return before.IsZero() || !mtime.After(before)
+	}
+}
+
+// walkState holds the state of a single WalkDir call
+type walkState struct {
+	opts   WalkOptions
+	walker func(entry *DirEntry) error
+
+	// ancestors are the directories currently being walked, used to detect loops
+	ancestors []os.FileInfo
+}
+
+// walk visits the entries of dir, which are all at the given depth
+func (w *walkState) walk(dir Path, depth int) error {
+	if w.opts.MaxDepth > 0 && depth > w.opts.MaxDepth {
+		return nil
+	}
+
+	entries, err := readDirEntries(dir, w.opts.Sort)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		e := newDirEntry(dir, entry, depth)
+
+		if !w.opts.resolve(e, w.ancestors) {
+			continue
+		}
+
+		if err := w.visit(e); err != nil {
+			if err == filepath.SkipDir {
+				if e.IsDir() {
+					continue
+				}
+				return nil
+			}
+			return err
+		}
+
+		if e.IsDir() {
+			if err := w.descend(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// descend walks into the directory of the given entry
+func (w *walkState) descend(e *DirEntry) error {
+	if w.opts.FollowSymlinks {
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		w.ancestors = append(w.ancestors, info)
+		defer func() { w.ancestors = w.ancestors[:len(w.ancestors)-1] }()
+	}
+
+	return w.walk(e.Path(), e.Depth()+1)
+}
+
+// visit calls the walker function when the entry is selected by the options
+func (w *walkState) visit(e *DirEntry) error {
+	if ok, err := w.opts.selects(e); !ok {
+		return err
+	}
+
+	return w.walker(e)
+}
+
+// resolve replaces the information of a symbolic link entry by the one of its
+// target when the options ask to follow links. It returns false when the link
+// leads back to one of the given ancestors, and so must not be visited.
+func (opts *WalkOptions) resolve(e *DirEntry, ancestors []os.FileInfo) bool {
+	if e.Type()&os.ModeSymlink == 0 || !opts.FollowSymlinks {
+		return true
+	}
+
+	// dangling links are reported as they are
+	target, err := os.Stat(e.Path().String())
+	if err != nil {
+		return true
+	}
+
+	if target.IsDir() {
+		for _, ancestor := range ancestors {
+			if os.SameFile(ancestor, target) {
+				return false
+			}
+		}
+	}
+
+	e.info = target
+	return true
+}
+
+// selects reports whether the entry should be passed to the walker function
+func (opts *WalkOptions) selects(e *DirEntry) (bool, error) {
+	if e.Depth() < opts.MinDepth {
+		return false, nil
+	}
+
+	if e.IsDir() && opts.Type == WalkFiles {
+		return false, nil
+	}
+
+	if !e.IsDir() && opts.Type == WalkDirs {
+		return false, nil
+	}
+
+	if len(opts.Filters) > 0 {
+		info, err := e.Info()
+		if err != nil {
+			// entries removed while walking are ignored
+			if os.IsNotExist(err) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		for _, filter := range opts.Filters {
+			if !filter(e.Path(), info) {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// readDirEntries returns the entries of a directory in the given order
+func readDirEntries(dir Path, order SortOrder) ([]os.DirEntry, error) {
+	f, err := os.Open(dir.String())
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer f.Close()
+
+	entries, err := f.ReadDir(-1)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	sortEntries(entries, order)
+	return entries, nil
+}
+
+// dirPageSize is the number of entries read at once by readDirPages
+const dirPageSize = 256
+
+// readDirPages calls fn with the entries of dir in the given order. Without
+// sorting, the directory is read in pages, so it is never held whole in memory.
+// The errors returned by fn stop the reading and are returned.
+func readDirPages(dir Path, order SortOrder, fn func(entries []os.DirEntry) error) error {
+	if order != SortNone {
+		entries, err := readDirEntries(dir, order)
+		if err != nil {
+			return err
+		}
+		return fn(entries)
+	}
+
+	f, err := os.Open(dir.String())
+	if err != nil {
+		return wrapError(err)
+	}
+	defer f.Close()
+
+	for {
+		entries, err := f.ReadDir(dirPageSize)
+		if len(entries) > 0 {
+			if err := fn(entries); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return wrapError(err)
+		}
+	}
+}
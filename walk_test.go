@@ -0,0 +1,337 @@
+package fs_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func createWalkTree(root fs.Path) error {
+	/*
+		├── a.txt
+		├── b
+		│   ├── c.log
+		│   └── d
+		│       └── e
+		│           └── f.txt
+		└── g.log
+	*/
+	files := []struct {
+		path    fs.Path
+		content string
+	}{
+		{path: root.Join("a.txt"), content: "a"},
+		{path: root.Join("b/c.log"), content: "ccc"},
+		{path: root.Join("b/d/e/f.txt"), content: "ffffff"},
+		{path: root.Join("g.log"), content: "gg"},
+	}
+
+	for _, f := range files {
+		file, err := f.path.Create()
+		if err != nil {
+			return err
+		}
+
+		_, err = file.Write([]byte(f.content))
+		file.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func walkRelative(root fs.Path, opts fs.WalkOptions) ([]string, error) {
+	var paths []string
+
+	err := root.WalkWithOptions(opts, func(path fs.Path, isDirectory bool) error {
+		rel, err := filepath.Rel(root.String(), path.String())
+		paths = append(paths, rel)
+		return err
+	})
+
+	return paths, err
+}
+
+func TestWalkWithOptions(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createWalkTree(root); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		tests := []struct {
+			opts     fs.WalkOptions
+			expected []string
+		}{
+			{
+				opts:     fs.WalkOptions{Sort: fs.SortByName},
+				expected: []string{"a.txt", "b", "b/c.log", "b/d", "b/d/e", "b/d/e/f.txt", "g.log"},
+			},
+			{
+				opts:     fs.WalkOptions{Type: fs.WalkFiles, Sort: fs.SortByName},
+				expected: []string{"a.txt", "b/c.log", "b/d/e/f.txt", "g.log"},
+			},
+			{
+				opts:     fs.WalkOptions{Type: fs.WalkDirs, Sort: fs.SortByName},
+				expected: []string{"b", "b/d", "b/d/e"},
+			},
+			{
+				opts:     fs.WalkOptions{MaxDepth: 1, Sort: fs.SortByName},
+				expected: []string{"a.txt", "b", "g.log"},
+			},
+			{
+				opts:     fs.WalkOptions{MinDepth: 2, MaxDepth: 3, Sort: fs.SortByName},
+				expected: []string{"b/c.log", "b/d", "b/d/e"},
+			},
+			{
+				opts:     fs.WalkOptions{Sort: fs.SortByName, Filters: []fs.WalkFilter{fs.NameFilter("*.log")}},
+				expected: []string{"b/c.log", "g.log"},
+			},
+			{
+				opts:     fs.WalkOptions{Sort: fs.SortByName, Filters: []fs.WalkFilter{fs.SizeFilter(2, 3)}},
+				expected: []string{"b/c.log", "g.log"},
+			},
+			{
+				opts: fs.WalkOptions{Sort: fs.SortByName, Filters: []fs.WalkFilter{
+					fs.NameFilter("*.txt"),
+					fs.SizeFilter(4, 0),
+				}},
+				expected: []string{"b/d/e/f.txt"},
+			},
+			{
+				opts:     fs.WalkOptions{Sort: fs.SortByName, Filters: []fs.WalkFilter{fs.ModTimeFilter(time.Now().Add(time.Hour), time.Time{})}},
+				expected: nil,
+			},
+		}
+
+		for i, test := range tests {
+			received, err := walkRelative(root, test.opts)
+			if err != nil {
+				t.Errorf("Case %d, error walking: %v", i, err)
+				continue
+			}
+
+			if !reflect.DeepEqual(received, test.expected) {
+				t.Errorf("Case %d, error testing walk with options: expected '%v', received '%v'", i, test.expected, received)
+			}
+		}
+	})
+}
+
+func TestWalkWithOptionsStop(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createWalkTree(root); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		tests := []struct {
+			returned error
+			expected error
+			visited  int
+		}{
+			{returned: fs.ErrStopWalk, expected: nil, visited: 2},
+			{returned: filepath.SkipDir, expected: nil, visited: 3},
+			{returned: fs.ErrNotFound, expected: fs.ErrNotFound, visited: 2},
+		}
+
+		for i, test := range tests {
+			visited := 0
+			err := root.WalkWithOptions(fs.WalkOptions{Sort: fs.SortByName}, func(path fs.Path, isDirectory bool) error {
+				visited++
+				if path.Basename() == "b" {
+					return test.returned
+				}
+				return nil
+			})
+
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Case %d, error testing walk stop: expected '%v', received '%v'", i, test.expected, err)
+			}
+
+			if visited != test.visited {
+				t.Errorf("Case %d, error testing walk stop: expected %d visits, received %d", i, test.visited, visited)
+			}
+		}
+	})
+}
+
+func TestWalkWithOptionsSymlinks(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createWalkTree(root); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		// a link to the tree itself, which would loop forever if followed blindly
+		if err := os.Symlink(dir, root.Join("b/loop").String()); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		// a link to a sibling directory
+		if err := os.Symlink(root.Join("b/d").String(), root.Join("h").String()); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		tests := []struct {
+			follow   bool
+			expected []string
+		}{
+			{
+				follow:   false,
+				expected: []string{"a.txt", "b/c.log", "b/d/e/f.txt", "b/loop", "g.log", "h"},
+			},
+			{
+				follow:   true,
+				expected: []string{"a.txt", "b/c.log", "b/d/e/f.txt", "g.log", "h/e/f.txt"},
+			},
+		}
+
+		for i, test := range tests {
+			opts := fs.WalkOptions{Type: fs.WalkFiles, Sort: fs.SortByName, FollowSymlinks: test.follow}
+
+			received, err := walkRelative(root, opts)
+			if err != nil {
+				t.Errorf("Case %d, error walking: %v", i, err)
+				continue
+			}
+
+			if !reflect.DeepEqual(received, test.expected) {
+				t.Errorf("Case %d, error testing walk symlinks: expected '%v', received '%v'", i, test.expected, received)
+			}
+		}
+	})
+}
+
+func TestWalkDir(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createWalkTree(root); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		sizes := make(map[string]int64)
+		depths := make(map[string]int)
+
+		if err := root.WalkDir(fs.WalkOptions{Type: fs.WalkFiles}, func(entry *fs.DirEntry) error {
+			if entry.Name() != entry.Path().Basename() {
+				t.Errorf("Unexpected entry path '%s' with name '%s'", entry.Path(), entry.Name())
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+
+			sizes[entry.Name()] = info.Size()
+			depths[entry.Name()] = entry.Depth()
+			return nil
+		}); err != nil {
+			t.Errorf("Error walking: %v", err)
+		}
+
+		expectedSizes := map[string]int64{"a.txt": 1, "c.log": 3, "f.txt": 6, "g.log": 2}
+		if !reflect.DeepEqual(sizes, expectedSizes) {
+			t.Errorf("Error testing entry sizes: expected '%v', received '%v'", expectedSizes, sizes)
+		}
+
+		expectedDepths := map[string]int{"a.txt": 1, "c.log": 2, "f.txt": 4, "g.log": 1}
+		if !reflect.DeepEqual(depths, expectedDepths) {
+			t.Errorf("Error testing entry depths: expected '%v', received '%v'", expectedDepths, depths)
+		}
+	})
+}
+
+// createBenchmarkTree creates a tree with width directories on each of the
+// depth levels, each of them containing width files.
+func createBenchmarkTree(root fs.Path, width, depth int) error {
+	for i := 0; i < width; i++ {
+		f, err := root.Join(fmt.Sprintf("file%d", i)).Create()
+		if err != nil {
+			return err
+		}
+		f.Close()
+
+		if depth > 0 {
+			if err := createBenchmarkTree(root.Join(fmt.Sprintf("dir%d", i)), width, depth-1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func benchmarkWithTree(b *testing.B, bench func(root fs.Path)) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		if err := createBenchmarkTree(root, 10, 3); err != nil {
+			b.Fatal(err)
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			bench(root)
+		}
+	})
+}
+
+// BenchmarkFilepathWalk measures the former implementation of Walk, which
+// calls lstat on every entry.
+func BenchmarkFilepathWalk(b *testing.B) {
+	benchmarkWithTree(b, func(root fs.Path) {
+		_ = filepath.Walk(root.String(), func(path string, info os.FileInfo, err error) error {
+			return err
+		})
+	})
+}
+
+func BenchmarkWalk(b *testing.B) {
+	benchmarkWithTree(b, func(root fs.Path) {
+		_ = root.Walk(fs.WalkBoth, func(path fs.Path, isDirectory bool) error {
+			return nil
+		})
+	})
+}
+
+func BenchmarkWalkDir(b *testing.B) {
+	benchmarkWithTree(b, func(root fs.Path) {
+		_ = root.WalkDir(fs.WalkOptions{}, func(entry *fs.DirEntry) error {
+			return nil
+		})
+	})
+}
+
+// BenchmarkIoutilReadDir measures the former implementation of ReadDir, which
+// calls lstat on every entry.
+func BenchmarkIoutilReadDir(b *testing.B) {
+	benchmarkWithTree(b, func(root fs.Path) {
+		_, _ = ioutil.ReadDir(root.String())
+	})
+}
+
+func BenchmarkReadDir(b *testing.B) {
+	benchmarkWithTree(b, func(root fs.Path) {
+		_, _ = root.ReadDir()
+	})
+}
@@ -0,0 +1,310 @@
+package fs
+
+import (
+	"io"
+	"os"
+)
+
+// WalkerOptions configures the traversal done by a Walker
+type WalkerOptions struct {
+	WalkOptions
+
+	// PageSize is the maximum number of entries read from a directory at once.
+	// Zero means reading all the entries at once. It is only honored when the
+	// entries are not sorted, as sorting requires reading the whole directory.
+	PageSize int
+
+	// ResumeAfter makes the traversal start right after the given path, which is
+	// usually the last path returned by a previous Walker on the same directory.
+	// It requires the entries to be sorted by name.
+	ResumeAfter Path
+}
+
+// Walker traverses a directory tree one entry at a time, in the same order as
+// WalkDir. Only the directories in the path to the current entry are kept open.
+//
+//	w := root.Walker(fs.WalkerOptions{})
+//	defer w.Close()
+//	for w.Next() {
+//		fmt.Println(w.Path())
+//	}
+//	if err := w.Err(); err != nil {
+//		...
+//	}
+type Walker struct {
+	root   Path
+	opts   WalkerOptions
+	stack  []*walkerFrame
+	resume []string
+
+	current *DirEntry
+	descend bool
+	started bool
+	err     error
+}
+
+// walkerFrame is a directory being traversed by a Walker
+type walkerFrame struct {
+	dir     Path
+	depth   int
+	file    *os.File
+	entries []os.DirEntry
+	info    os.FileInfo
+}
+
+// Walker returns a Walker traversing the tree under the path
+func (p Path) Walker(opts WalkerOptions) *Walker {
+	return &Walker{root: p, opts: opts}
+}
+
+// Next advances the walker to the next entry selected by the options. It returns
+// false when there are no more entries or an error happened.
+func (w *Walker) Next() bool {
+	if !w.started {
+		w.started = true
+		if w.err = w.start(); w.err != nil {
+			w.Close()
+			return false
+		}
+	}
+
+	for w.err == nil {
+		if w.descend {
+			w.descend = false
+			if w.err = w.push(w.current); w.err != nil {
+				break
+			}
+		}
+
+		if len(w.stack) == 0 {
+			w.current = nil
+			return false
+		}
+
+		e, err := w.read()
+		if err != nil {
+			w.err = err
+			break
+		}
+
+		if e == nil {
+			w.pop()
+			continue
+		}
+
+		canDescend := w.opts.MaxDepth <= 0 || e.Depth() < w.opts.MaxDepth
+
+		if !w.opts.resolve(e, w.ancestors()) {
+			continue
+		}
+
+		if w.resume != nil {
+			if visit, descend := w.resumed(e); !visit {
+				w.current = e
+				w.descend = e.IsDir() && canDescend && descend
+				continue
+			}
+		}
+
+		ok, err := w.opts.selects(e)
+		if err != nil {
+			w.err = err
+			break
+		}
+
+		w.current = e
+		w.descend = e.IsDir() && canDescend
+
+		if ok {
+			return true
+		}
+	}
+
+	w.current = nil
+	w.Close()
+	return false
+}
+
+// Path returns the path of the current entry
+func (w *Walker) Path() Path {
+	if w.current == nil {
+		return ""
+	}
+	return w.current.Path()
+}
+
+// Entry returns the current entry
+func (w *Walker) Entry() *DirEntry {
+	return w.current
+}
+
+// Err returns the error that stopped the walker, if any
+func (w *Walker) Err() error {
+	return w.err
+}
+
+// SkipDir makes the walker skip the current entry when it is a directory,
+// or the remaining entries of its parent directory otherwise.
+func (w *Walker) SkipDir() {
+	if w.current == nil {
+		return
+	}
+
+	if w.current.IsDir() {
+		w.descend = false
+		return
+	}
+
+	// the parent is only skipped once, even if called again
+	if len(w.stack) > 0 {
+		w.pop()
+	}
+	w.current = nil
+}
+
+// Close releases the directories kept open by the walker. It is only needed
+// when the walker is not used until Next returns false.
+func (w *Walker) Close() error {
+	w.current = nil
+	w.descend = false
+
+	var err error
+	for len(w.stack) > 0 {
+		if e := w.pop(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// start opens the root directory and prepares the resuming point
+func (w *Walker) start() error {
+	info, err := statDir("walk", w.root)
+	if err != nil {
+		return err
+	}
+
+	if !w.opts.ResumeAfter.Empty() {
+		if w.opts.Sort != SortByName {
+			return newError("walk", w.opts.ResumeAfter, ErrCannotResume)
+		}
+
+		if !w.opts.ResumeAfter.IsChildOf(w.root) {
+			return newError("walk", w.opts.ResumeAfter, ErrCannotResume)
+		}
+
+		rel, err := w.opts.ResumeAfter.Abs().Rel(w.root.Abs())
+		if err != nil {
+			return newError("walk", w.opts.ResumeAfter, ErrCannotResume)
+		}
+		w.resume = rel.Components()
+	}
+
+	return w.open(w.root, 1, info)
+}
+
+// push starts traversing the directory of the given entry
+func (w *Walker) push(e *DirEntry) error {
+	var info os.FileInfo
+	if w.opts.FollowSymlinks {
+		var err error
+		if info, err = e.Info(); err != nil {
+			return err
+		}
+	}
+
+	return w.open(e.Path(), e.Depth()+1, info)
+}
+
+// open adds a directory to the stack of directories being traversed
+func (w *Walker) open(dir Path, depth int, info os.FileInfo) error {
+	frame := &walkerFrame{dir: dir, depth: depth, info: info}
+
+	if w.opts.Sort != SortNone || w.opts.PageSize <= 0 {
+		entries, err := readDirEntries(dir, w.opts.Sort)
+		if err != nil {
+			return err
+		}
+		frame.entries = entries
+	} else {
+		file, err := os.Open(dir.String())
+		if err != nil {
+			return wrapError(err)
+		}
+		frame.file = file
+	}
+
+	w.stack = append(w.stack, frame)
+	return nil
+}
+
+// pop stops traversing the innermost directory
+func (w *Walker) pop() error {
+	frame := w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+
+	if frame.file != nil {
+		return frame.file.Close()
+	}
+	return nil
+}
+
+// read returns the next entry of the innermost directory, or nil when there
+// are no entries left on it.
+func (w *Walker) read() (*DirEntry, error) {
+	frame := w.stack[len(w.stack)-1]
+
+	if len(frame.entries) == 0 && frame.file != nil {
+		entries, err := frame.file.ReadDir(w.opts.PageSize)
+		if err != nil && err != io.EOF {
+			return nil, wrapError(err)
+		}
+		frame.entries = entries
+	}
+
+	if len(frame.entries) == 0 {
+		return nil, nil
+	}
+
+	entry := frame.entries[0]
+	frame.entries = frame.entries[1:]
+
+	return newDirEntry(frame.dir, entry, frame.depth), nil
+}
+
+// ancestors returns the information of the directories being traversed
+func (w *Walker) ancestors() []os.FileInfo {
+	if !w.opts.FollowSymlinks {
+		return nil
+	}
+
+	ancestors := make([]os.FileInfo, 0, len(w.stack))
+	for _, frame := range w.stack {
+		if frame.info != nil {
+			ancestors = append(ancestors, frame.info)
+		}
+	}
+	return ancestors
+}
+
+// resumed reports whether an entry comes after the resuming point, and so must
+// be visited, and whether it must be descended into to reach the resuming point.
+func (w *Walker) resumed(e *DirEntry) (visit, descend bool) {
+	// only the directories leading to the resuming point are descended into,
+	// so the entry is a sibling of the component at the same depth
+	point := w.resume[e.Depth()-1]
+
+	switch {
+	case e.Name() < point:
+		return false, false
+	case e.Name() > point:
+		w.resume = nil
+		return true, true
+	case e.Depth() == len(w.resume):
+		// the resuming point itself, everything after it is visited
+		w.resume = nil
+		return false, true
+	default:
+		return false, true
+	}
+}
@@ -0,0 +1,202 @@
+package fs_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+func collectWalker(w *fs.Walker) ([]fs.Path, error) {
+	var paths []fs.Path
+	for w.Next() {
+		paths = append(paths, w.Path())
+	}
+	return paths, w.Err()
+}
+
+func TestWalker(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createBenchmarkTree(root, 3, 2); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		tests := []fs.WalkerOptions{
+			{WalkOptions: fs.WalkOptions{Sort: fs.SortByName}},
+			{WalkOptions: fs.WalkOptions{Type: fs.WalkFiles, Sort: fs.SortByName, MaxDepth: 2}},
+			{WalkOptions: fs.WalkOptions{Type: fs.WalkDirs}, PageSize: 2},
+			{WalkOptions: fs.WalkOptions{MinDepth: 2}, PageSize: 1},
+		}
+
+		for i, opts := range tests {
+			var expected []fs.Path
+			if err := root.WalkDir(opts.WalkOptions, func(entry *fs.DirEntry) error {
+				expected = append(expected, entry.Path())
+				return nil
+			}); err != nil {
+				t.Errorf("Case %d, error walking: %v", i, err)
+				continue
+			}
+
+			received, err := collectWalker(root.Walker(opts))
+			if err != nil {
+				t.Errorf("Case %d, error iterating: %v", i, err)
+				continue
+			}
+
+			if opts.Sort == fs.SortNone {
+				sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+				sort.Slice(received, func(i, j int) bool { return received[i] < received[j] })
+			}
+
+			if !reflect.DeepEqual(received, expected) {
+				t.Errorf("Case %d, error testing walker: expected '%v', received '%v'", i, expected, received)
+			}
+		}
+
+		if _, err := collectWalker(root.Join("none").Walker(fs.WalkerOptions{})); !errors.Is(err, fs.ErrDirDoesNotExist) {
+			t.Errorf("Error testing walker on missing directory: expected '%v', received '%v'", fs.ErrDirDoesNotExist, err)
+		}
+	})
+}
+
+func TestWalkerSkipDir(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createWalkTree(root); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		var received []fs.Path
+		w := root.Walker(fs.WalkerOptions{WalkOptions: fs.WalkOptions{Sort: fs.SortByName}})
+		defer w.Close()
+
+		for w.Next() {
+			received = append(received, w.Path())
+
+			switch w.Entry().Name() {
+			case "d":
+				w.SkipDir()
+			case "c.log":
+				if w.Entry().IsDir() {
+					t.Errorf("Entry '%s' should not be a directory", w.Path())
+				}
+			}
+		}
+
+		if err := w.Err(); err != nil {
+			t.Errorf("Error iterating: %v", err)
+		}
+
+		expected := []fs.Path{root.Join("a.txt"), root.Join("b"), root.Join("b/c.log"), root.Join("b/d"), root.Join("g.log")}
+		if !reflect.DeepEqual(received, expected) {
+			t.Errorf("Error testing walker skip dir: expected '%v', received '%v'", expected, received)
+		}
+	})
+}
+
+func TestWalkerSkipDirTwice(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createWalkTree(root); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		var received []fs.Path
+		w := root.Walker(fs.WalkerOptions{WalkOptions: fs.WalkOptions{Sort: fs.SortByName}})
+
+		for w.Next() {
+			received = append(received, w.Path())
+
+			// only the rest of the parent directory is skipped
+			if w.Entry().Name() == "c.log" {
+				w.SkipDir()
+				w.SkipDir()
+			}
+		}
+
+		if err := w.Err(); err != nil {
+			t.Errorf("Error iterating: %v", err)
+		}
+
+		expected := []fs.Path{root.Join("a.txt"), root.Join("b"), root.Join("b/c.log"), root.Join("g.log")}
+		if !reflect.DeepEqual(received, expected) {
+			t.Errorf("Error testing walker skip dir twice: expected '%v', received '%v'", expected, received)
+		}
+
+		// a closed walker has nothing left to skip
+		w = root.Walker(fs.WalkerOptions{})
+		if !w.Next() {
+			t.Errorf("Error iterating: %v", w.Err())
+		}
+		w.Close()
+		w.SkipDir()
+
+		if w.Next() || w.Entry() != nil {
+			t.Errorf("Error testing closed walker: received '%s'", w.Path())
+		}
+	})
+}
+
+func TestWalkerResume(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		if err := createBenchmarkTree(root, 3, 2); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		opts := fs.WalkerOptions{WalkOptions: fs.WalkOptions{Sort: fs.SortByName}}
+		all, err := collectWalker(root.Walker(opts))
+		if err != nil {
+			t.Errorf("Error iterating: %v", err)
+			return
+		}
+
+		for i, checkpoint := range all {
+			opts.ResumeAfter = checkpoint
+
+			received, err := collectWalker(root.Walker(opts))
+			if err != nil {
+				t.Errorf("Case %d, error resuming: %v", i, err)
+				continue
+			}
+
+			if expected := all[i+1:]; len(received)+len(expected) > 0 && !reflect.DeepEqual(received, expected) {
+				t.Errorf("Case %d, error resuming after '%s': expected '%v', received '%v'", i, checkpoint, expected, received)
+			}
+		}
+
+		// resuming from a path that was removed in the meantime
+		opts.ResumeAfter = root.Join("dir1/dir0/file05")
+		received, err := collectWalker(root.Walker(opts))
+		if err != nil {
+			t.Errorf("Error resuming from a missing path: %v", err)
+		}
+
+		if len(received) == 0 || received[0] != root.Join("dir1/dir0/file1") {
+			t.Errorf("Error resuming from a missing path, received '%v'", received)
+		}
+
+		tests := []fs.WalkerOptions{
+			{ResumeAfter: root.Join("dir0")},
+			{WalkOptions: fs.WalkOptions{Sort: fs.SortByName}, ResumeAfter: root.Parent()},
+		}
+
+		for i, opts := range tests {
+			if _, err := collectWalker(root.Walker(opts)); !errors.Is(err, fs.ErrCannotResume) {
+				t.Errorf("Case %d, error testing invalid resume: expected '%v', received '%v'", i, fs.ErrCannotResume, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package fs
+
+import (
+	"bytes"
+	"syscall"
+)
+
+// GetXattr returns the value of the extended attribute of the path
+func (p Path) GetXattr(name string) ([]byte, error) {
+	for {
+		size, err := syscall.Getxattr(p.String(), name, nil)
+		if err != nil {
+			return nil, xattrError("getxattr", p, err)
+		}
+
+		value := make([]byte, size)
+		size, err = syscall.Getxattr(p.String(), name, value)
+
+		// the value may grow between the calls
+		if err == syscall.ERANGE {
+			continue
+		}
+		if err != nil {
+			return nil, xattrError("getxattr", p, err)
+		}
+
+		return value[:size], nil
+	}
+}
+
+// SetXattr sets the value of the extended attribute of the path, creating it when needed
+func (p Path) SetXattr(name string, value []byte) error {
+	if err := syscall.Setxattr(p.String(), name, value, 0); err != nil {
+		return xattrError("setxattr", p, err)
+	}
+	return nil
+}
+
+// ListXattr returns the names of the extended attributes of the path
+func (p Path) ListXattr() ([]string, error) {
+	for {
+		size, err := syscall.Listxattr(p.String(), nil)
+		if err != nil {
+			return nil, xattrError("listxattr", p, err)
+		}
+
+		if size == 0 {
+			return nil, nil
+		}
+
+		list := make([]byte, size)
+		size, err = syscall.Listxattr(p.String(), list)
+
+		// the list may grow between the calls
+		if err == syscall.ERANGE {
+			continue
+		}
+		if err != nil {
+			return nil, xattrError("listxattr", p, err)
+		}
+
+		// the names are separated by null characters
+		var names []string
+		for _, name := range bytes.Split(list[:size], []byte{0}) {
+			if len(name) > 0 {
+				names = append(names, string(name))
+			}
+		}
+		return names, nil
+	}
+}
+
+// RemoveXattr removes the extended attribute of the path
+func (p Path) RemoveXattr(name string) error {
+	if err := syscall.Removexattr(p.String(), name); err != nil {
+		return xattrError("removexattr", p, err)
+	}
+	return nil
+}
+
+// xattrError creates the error of a extended attribute operation
+func xattrError(op string, p Path, err error) error {
+	switch err {
+	case syscall.ENODATA:
+		err = ErrNoXattr
+	case syscall.ENOTSUP:
+		err = ErrNotSupported
+	}
+	return newError(op, p, err)
+}
@@ -0,0 +1,28 @@
+//go:build !linux
+// +build !linux
+
+package fs
+
+// GetXattr returns the value of the extended attribute of the path,
+// which is not supported on this platform.
+func (p Path) GetXattr(name string) ([]byte, error) {
+	return nil, newError("getxattr", p, ErrNotSupported)
+}
+
+// SetXattr sets the value of the extended attribute of the path,
+// which is not supported on this platform.
+func (p Path) SetXattr(name string, value []byte) error {
+	return newError("setxattr", p, ErrNotSupported)
+}
+
+// ListXattr returns the names of the extended attributes of the path,
+// which are not supported on this platform.
+func (p Path) ListXattr() ([]string, error) {
+	return nil, newError("listxattr", p, ErrNotSupported)
+}
+
+// RemoveXattr removes the extended attribute of the path,
+// which is not supported on this platform.
+func (p Path) RemoveXattr(name string) error {
+	return newError("removexattr", p, ErrNotSupported)
+}
@@ -0,0 +1,132 @@
+package fs_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+// requireXattrs skips the test when the filesystem does not support extended attributes
+func requireXattrs(t *testing.T, path fs.Path) {
+	if err := path.SetXattr("user.test", nil); errors.Is(err, fs.ErrNotSupported) {
+		t.Skip("extended attributes are not supported")
+	}
+	_ = path.RemoveXattr("user.test")
+}
+
+func TestXattr(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		file := root.Join("file.txt")
+
+		f, err := file.Create()
+		if err != nil {
+			t.Errorf("Error creating file: %v", err)
+			return
+		}
+		f.Close()
+
+		requireXattrs(t, file)
+
+		if names, err := file.ListXattr(); err != nil || len(names) != 0 {
+			t.Errorf("Error testing ListXattr without attributes: %v %v", names, err)
+		}
+
+		attrs := map[string][]byte{
+			"user.origin":   []byte("https://example.com/file.txt"),
+			"user.checksum": []byte("sha256:0123456789abcdef"),
+			"user.empty":    {},
+		}
+
+		for name, value := range attrs {
+			if err := file.SetXattr(name, value); err != nil {
+				t.Errorf("Error setting '%s': %v", name, err)
+			}
+		}
+
+		for name, value := range attrs {
+			if received, err := file.GetXattr(name); err != nil || !reflect.DeepEqual(received, value) {
+				t.Errorf("Error testing GetXattr '%s': expected '%s', received '%s' (%v)", name, value, received, err)
+			}
+		}
+
+		names, err := file.ListXattr()
+		if err != nil {
+			t.Errorf("Error listing attributes: %v", err)
+		}
+		sort.Strings(names)
+
+		if expected := []string{"user.checksum", "user.empty", "user.origin"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("Error testing ListXattr: expected '%v', received '%v'", expected, names)
+		}
+
+		if err := file.RemoveXattr("user.origin"); err != nil {
+			t.Errorf("Error removing attribute: %v", err)
+		}
+
+		tests := []struct {
+			err      error
+			expected error
+		}{
+			{err: errorOf(file.GetXattr("user.origin")), expected: fs.ErrNoXattr},
+			{err: file.RemoveXattr("user.origin"), expected: fs.ErrNoXattr},
+			{err: errorOf(root.Join("missing").GetXattr("user.origin")), expected: fs.ErrNotFound},
+			{err: root.Join("missing").SetXattr("user.origin", nil), expected: fs.ErrNotFound},
+			{err: errorOf(root.Join("missing").ListXattr()), expected: fs.ErrNotFound},
+		}
+
+		for i, test := range tests {
+			if !errors.Is(test.err, test.expected) {
+				t.Errorf("Case %d, error testing xattr errors: expected '%v', received '%v'", i, test.expected, test.err)
+			}
+		}
+	})
+}
+
+func TestCopyToWithOptionsXattrs(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+		src := root.Join("src")
+
+		if err := createWalkTree(src); err != nil {
+			t.Errorf("Error creating tree: %v", err)
+			return
+		}
+
+		requireXattrs(t, src)
+
+		paths := []string{"", "a.txt", "b/d", "b/d/e/f.txt"}
+		for _, path := range paths {
+			if err := src.Join(path).SetXattr("user.origin", []byte(path)); err != nil {
+				t.Errorf("Error setting attribute: %v", err)
+				return
+			}
+		}
+
+		tests := []bool{false, true}
+
+		for i, preserve := range tests {
+			dest := root.Join("dest").Join(string(rune('a' + i)))
+
+			if err := src.CopyToWithOptions(dest, fs.CopyOptions{PreserveXattrs: preserve}); err != nil {
+				t.Errorf("Case %d, error copying: %v", i, err)
+				continue
+			}
+
+			for _, path := range paths {
+				value, err := dest.Join(path).GetXattr("user.origin")
+
+				if preserve && (err != nil || string(value) != path) {
+					t.Errorf("Case %d, error testing preserved attribute of '%s': received '%s' (%v)", i, path, value, err)
+				}
+
+				if !preserve && !errors.Is(err, fs.ErrNoXattr) {
+					t.Errorf("Case %d, attribute of '%s' should not be copied: received '%s' (%v)", i, path, value, err)
+				}
+			}
+		}
+	})
+}
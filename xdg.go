@@ -0,0 +1,147 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// xdgDirMode is the mode of the base directories created, as required by the
+// XDG Base Directory specification.
+const xdgDirMode os.FileMode = 0700
+
+// xdgFilesystem creates the base directories and the ones of the applications
+var xdgFilesystem = &Filesystem{DirMode: xdgDirMode, ParentDirMode: xdgDirMode}
+
+// ConfigDir returns the directory of the configuration files of the application,
+// inside $XDG_CONFIG_HOME or ~/.config, creating it when needed.
+func ConfigDir(app string) (Path, error) {
+	return xdgDir(app, "XDG_CONFIG_HOME", ".config")
+}
+
+// CacheDir returns the directory of the cached files of the application, inside
+// $XDG_CACHE_HOME or ~/.cache, creating it when needed.
+func CacheDir(app string) (Path, error) {
+	return xdgDir(app, "XDG_CACHE_HOME", ".cache")
+}
+
+// DataDir returns the directory of the data files of the application, inside
+// $XDG_DATA_HOME or ~/.local/share, creating it when needed.
+func DataDir(app string) (Path, error) {
+	return xdgDir(app, "XDG_DATA_HOME", ".local/share")
+}
+
+// StateDir returns the directory of the state files of the application, like
+// logs and history, inside $XDG_STATE_HOME or ~/.local/state, creating it when
+// needed.
+func StateDir(app string) (Path, error) {
+	return xdgDir(app, "XDG_STATE_HOME", ".local/state")
+}
+
+// RuntimeDir returns the directory of the runtime files of the application,
+// like sockets, inside $XDG_RUNTIME_DIR, creating it when needed. There is no
+// default, so a error is returned when the variable is not set.
+func RuntimeDir(app string) (Path, error) {
+	if err := checkApp("runtimedir", app); err != nil {
+		return "", err
+	}
+
+	base := os.Getenv("XDG_RUNTIME_DIR")
+	if !filepath.IsAbs(base) {
+		return "", newError("runtimedir", Path(base), ErrDirDoesNotExist)
+	}
+
+	dir := Path(base).Join(app)
+	if err := xdgFilesystem.MkdirAll(dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// FindConfig returns the first existing configuration file of the application
+// with the given name, searching the directory returned by ConfigDir and then
+// the directories in $XDG_CONFIG_DIRS, or /etc/xdg, in order. The name is
+// resolved with SecureJoin, so it can't lead outside of the directory of the
+// application.
+func FindConfig(app, name string) (Path, error) {
+	if err := checkApp("findconfig", app); err != nil {
+		return "", err
+	}
+
+	home, err := xdgHome("XDG_CONFIG_HOME", ".config")
+	if err != nil {
+		return "", err
+	}
+
+	dirs := append([]Path{home}, xdgDirs("XDG_CONFIG_DIRS", "/etc/xdg")...)
+	for _, dir := range dirs {
+		file, err := dir.Join(app).SecureJoin(name)
+		if err != nil {
+			return "", err
+		}
+
+		if file.FileExists() {
+			return file, nil
+		}
+	}
+
+	return "", newError("findconfig", Path(app).Join(name), ErrFileDoesNotExist)
+}
+
+// xdgDir returns the directory of the application in a base directory,
+// creating it when needed.
+func xdgDir(app, env, fallback string) (Path, error) {
+	if err := checkApp("xdgdir", app); err != nil {
+		return "", err
+	}
+
+	base, err := xdgHome(env, fallback)
+	if err != nil {
+		return "", err
+	}
+
+	dir := base.Join(app)
+	if err := xdgFilesystem.MkdirAll(dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// checkApp fails with ErrInvalidName when the name of the application isn't a
+// single component, so it can't lead outside of the base directories.
+func checkApp(op, app string) error {
+	if app == "" || app == "." || app == ".." || strings.ContainsAny(app, `/\`) {
+		return newError(op, Path(app), ErrInvalidName)
+	}
+	return nil
+}
+
+// xdgHome returns the base directory in the environment variable or, when it
+// is not set to a absolute path, the fallback inside the home directory.
+func xdgHome(env, fallback string) (Path, error) {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return Path(dir), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return Path(home).Join(fallback), nil
+}
+
+// xdgDirs returns the absolute directories in the list of the environment
+// variable, or the fallback when there are none.
+func xdgDirs(env, fallback string) []Path {
+	var dirs []Path
+	for _, dir := range strings.Split(os.Getenv(env), string(filepath.ListSeparator)) {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, Path(dir))
+		}
+	}
+
+	if len(dirs) == 0 {
+		dirs = append(dirs, Path(fallback))
+	}
+	return dirs
+}
@@ -0,0 +1,155 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/plateausnetwork/fs"
+)
+
+// withEnv sets the environment variables while the handler runs, restoring
+// their previous values afterwards. Empty values unset the variables.
+func withEnv(env map[string]string, handler func()) {
+	for key, value := range env {
+		previous, ok := os.LookupEnv(key)
+		defer func(key string) {
+			if ok {
+				os.Setenv(key, previous)
+			} else {
+				os.Unsetenv(key)
+			}
+		}(key)
+
+		if value == "" {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, value)
+		}
+	}
+
+	handler()
+}
+
+func TestXDGDirs(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		env := map[string]string{
+			"HOME":            root.Join("home").String(),
+			"XDG_CONFIG_HOME": root.Join("config").String(),
+			"XDG_CACHE_HOME":  "relative/is/ignored",
+			"XDG_DATA_HOME":   "",
+			"XDG_STATE_HOME":  root.Join("state").String(),
+			"XDG_RUNTIME_DIR": root.Join("run").String(),
+		}
+
+		withEnv(env, func() {
+			tests := []struct {
+				dir      func(app string) (fs.Path, error)
+				expected fs.Path
+			}{
+				{dir: fs.ConfigDir, expected: root.Join("config/app")},
+				{dir: fs.CacheDir, expected: root.Join("home/.cache/app")},
+				{dir: fs.DataDir, expected: root.Join("home/.local/share/app")},
+				{dir: fs.StateDir, expected: root.Join("state/app")},
+				{dir: fs.RuntimeDir, expected: root.Join("run/app")},
+			}
+
+			for i, test := range tests {
+				received, err := test.dir("app")
+				if err != nil || received != test.expected {
+					t.Errorf("Case %d, error testing xdg directory: expected '%s', received '%s' (%v)", i, test.expected, received, err)
+					continue
+				}
+
+				if mode, err := received.Mode(); err != nil || mode != os.ModeDir|0700 {
+					t.Errorf("Case %d, error testing mode of '%s': expected '%v', received '%v' (%v)", i, received, os.ModeDir|0700, mode, err)
+				}
+
+				// existing directories are returned again
+				if again, err := test.dir("app"); err != nil || again != received {
+					t.Errorf("Case %d, error testing existing xdg directory: received '%s' (%v)", i, again, err)
+				}
+
+				// the application must be a single component
+				for _, app := range []string{"", ".", "..", "../app", "app/sub", `app\sub`} {
+					if _, err := test.dir(app); !errors.Is(err, fs.ErrInvalidName) {
+						t.Errorf("Case %d, error testing xdg directory of '%s': expected '%v', received '%v'", i, app, fs.ErrInvalidName, err)
+					}
+				}
+			}
+
+			if mode, _ := root.Join("home/.local").Mode(); mode != os.ModeDir|0700 {
+				t.Errorf("Error testing mode of created base directory: expected '%v', received '%v'", os.ModeDir|0700, mode)
+			}
+		})
+
+		withEnv(map[string]string{"XDG_RUNTIME_DIR": ""}, func() {
+			if _, err := fs.RuntimeDir("app"); !errors.Is(err, fs.ErrNotFound) {
+				t.Errorf("Error testing runtime dir without the variable: expected '%v', received '%v'", fs.ErrNotFound, err)
+			}
+		})
+	})
+}
+
+func TestFindConfig(t *testing.T) {
+	WithTempDir(func(dir string) {
+		root := fs.Path(dir)
+
+		files := []fs.Path{
+			root.Join("config/app/user.toml"),
+			root.Join("etc1/app/user.toml"),
+			root.Join("etc1/app/system.toml"),
+			root.Join("etc2/app/system.toml"),
+			root.Join("etc2/app/other.toml"),
+			root.Join("config/app/dir/file.toml"),
+		}
+
+		for _, file := range files {
+			if err := file.Touch(); err != nil {
+				t.Errorf("Error creating file: %v", err)
+				return
+			}
+		}
+
+		if err := root.Join("config/app/out.toml").Symlink("../../etc2/app/other.toml"); err != nil {
+			t.Errorf("Error creating link: %v", err)
+			return
+		}
+
+		env := map[string]string{
+			"XDG_CONFIG_HOME": root.Join("config").String(),
+			"XDG_CONFIG_DIRS": root.Join("etc1").String() + string(os.PathListSeparator) + root.Join("etc2").String(),
+		}
+
+		withEnv(env, func() {
+			tests := []struct {
+				name     string
+				expected fs.Path
+				err      error
+			}{
+				{name: "user.toml", expected: root.Join("config/app/user.toml")},
+				{name: "system.toml", expected: root.Join("etc1/app/system.toml")},
+				{name: "other.toml", expected: root.Join("etc2/app/other.toml")},
+				{name: "missing.toml", err: fs.ErrNotFound},
+				{name: "dir", err: fs.ErrNotFound},
+				{name: "dir/file.toml", expected: root.Join("config/app/dir/file.toml")},
+				{name: "../../etc1/app/user.toml", err: fs.ErrOutsideRoot},
+				{name: "dir/../../app/user.toml", err: fs.ErrOutsideRoot},
+				{name: "out.toml", err: fs.ErrOutsideRoot},
+			}
+
+			for i, test := range tests {
+				received, err := fs.FindConfig("app", test.name)
+				if !errors.Is(err, test.err) || received != test.expected {
+					t.Errorf("Case %d, error testing find config: expected '%s' (%v), received '%s' (%v)", i, test.expected, test.err, received, err)
+				}
+			}
+
+			if _, err := fs.FindConfig("../config/app", "user.toml"); !errors.Is(err, fs.ErrInvalidName) {
+				t.Errorf("Error testing find config of a invalid application: expected '%v', received '%v'", fs.ErrInvalidName, err)
+			}
+		})
+	})
+}